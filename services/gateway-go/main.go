@@ -1,39 +1,244 @@
 package main
 
 import (
-	"log"
+	"context"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/artifacts"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/auth"
 	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/handlers"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/queue"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/resumable"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/secret"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
 	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/telemetry"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/upload"
 	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/worker"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/workerpool"
 )
 
+// drainTimeout bounds how long shutdown waits for in-flight tasks to
+// finish on their own before Store.CancelAll forces them to stop.
+const drainTimeout = 30 * time.Second
+
 func main() {
+	// rootCtx is cancelled the instant SIGINT/SIGTERM arrives. Workers
+	// watch it only to stop dequeuing new work; a task already in flight
+	// keeps running past that point (see worker.processTask) until it
+	// finishes or the drain deadline below forces it to stop.
+	rootCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger := telemetry.NewLogger()
+
+	shutdownTracer, err := telemetry.InitTracer(rootCtx)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("init tracer")
+	}
+	defer func() {
+		if err := shutdownTracer(context.Background()); err != nil {
+			logger.Error().Err(err).Msg("shutdown tracer")
+		}
+	}()
+
 	e := echo.New()
+	e.Use(telemetry.RequestLogger())
 
-	s := store.NewStore()
-	tasks := make(chan string, 100)
+	// STORE_DRIVER selects "memory" (default, single-replica, lost on
+	// restart) or "bolt" (single-node durable, the same pattern
+	// storage.ConfigFromEnv uses for STORAGE_DRIVER), so RecoverInterrupted
+	// below has something real to recover when it's set.
+	s, err := store.New(store.ConfigFromEnv())
+	if err != nil {
+		logger.Fatal().Err(err).Msg("create store")
+	}
 
-	// Routes
-	e.POST("/api/v1/upload", handlers.UploadHandler(s, tasks))
-	e.GET("/api/v1/tasks/:id", handlers.StatusHandler(s))
-	e.GET("/api/v1/report/:id", handlers.ReportHandler(s))
-	e.GET("/api/v1/download/:id", handlers.DownloadHandler(s))
+	// GATEWAY_QUEUE_DB_PATH selects a BoltPersistence-backed queue, so a
+	// restart redelivers whatever was queued or in-flight instead of
+	// silently dropping it; unset (the default) keeps the original
+	// in-memory-only queue for local runs and tests.
+	q, err := newQueue(os.Getenv("GATEWAY_QUEUE_DB_PATH"))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("create queue")
+	}
+
+	authenticator := newAuthenticator()
+	authMW := auth.Middleware(authenticator)
+	limiter := auth.NewTenantRateLimiter(10, 20)
+	signer := auth.NewURLSigner([]byte(os.Getenv("DOWNLOAD_SIGNING_SECRET")))
 
-	// Start worker
-	go worker.Worker(tasks, s)
+	// handoffKey backs the short-lived JWT that accompanies every task
+	// onto the queue, proving to a worker (in-process or an
+	// out-of-process caller of WorkerCallbackHandler) that it's the
+	// gateway that enqueued this exact task/file/hash.
+	handoffKey, err := secret.Load(os.Getenv("GATEWAY_SECRET_PATH"))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("load handoff secret")
+	}
+	handoffSigner := secret.NewHandoffSigner(handoffKey)
+
+	// backend is where UploadHandler/StatusHandler/FinalizeUploadSessionHandler
+	// put and stat uploaded sources; STORAGE_DRIVER selects local disk
+	// (default, single-replica) or an object store for horizontally
+	// scaled deployments that can't share a disk.
+	backend, err := storage.New(storage.ConfigFromEnv())
+	if err != nil {
+		logger.Fatal().Err(err).Msg("create storage backend")
+	}
+
+	// Accelerate is a no-op (PreAuthURL == "") unless
+	// GATEWAY_UPLOAD_PREAUTH_URL is set, so local/test deployments keep
+	// buffering uploads through UploadHandler's fallback path. When it
+	// does run, it streams straight into the same backend so an
+	// accelerated upload's SourcePath is indistinguishable from a
+	// non-accelerated one downstream.
+	uploadAccel := upload.Accelerate(upload.Config{
+		PreAuthURL: os.Getenv("GATEWAY_UPLOAD_PREAUTH_URL"),
+		Backend:    backend,
+		Timeout:    10 * time.Second,
+	}, "file")
+
+	sessions, err := resumable.NewManager(filepath.Join("..", "temp_docs", "sessions"))
+	if err != nil {
+		logger.Fatal().Err(err).Msg("create resumable upload session manager")
+	}
+
+	// Routes. /metrics and the signed download are intentionally
+	// unauthenticated: the former is scraped by Prometheus, the latter
+	// carries its own HMAC-signed credential (see auth.URLSigner).
+	protected := e.Group("/api/v1", authMW, limiter.Middleware())
+	protected.POST("/upload", handlers.UploadHandler(s, q, handoffSigner, backend), uploadAccel)
+	protected.POST("/upload/session", handlers.CreateUploadSessionHandler(sessions))
+	protected.PATCH("/upload/session/:sid", handlers.UploadChunkHandler(sessions))
+	protected.HEAD("/upload/session/:sid", handlers.UploadSessionStatusHandler(sessions))
+	protected.POST("/upload/session/:sid/finalize", handlers.FinalizeUploadSessionHandler(s, q, handoffSigner, backend, sessions))
+	protected.GET("/tasks/:id", handlers.StatusHandler(s, backend))
+	protected.DELETE("/tasks/:id", handlers.DeleteHandler(s))
+	protected.GET("/tasks/:id/events", handlers.EventsHandler(s))
+	protected.GET("/ws", handlers.WSHandler(s))
+	protected.GET("/report/:id", handlers.ReportHandler(s))
+	protected.GET("/download/:id", handlers.DownloadHandler(s, signer))
+	protected.GET("/queue/stats", handlers.QueueStatsHandler(q))
+	protected.GET("/tasks/:id/artifacts/metadata", artifacts.MetadataHandler(s, backend))
+	protected.GET("/tasks/:id/artifacts/file/:entry", artifacts.ExtractHandler(s, backend))
+
+	e.GET("/api/v1/download/signed", handlers.SignedDownloadHandler(signer))
+	// WorkerCallbackHandler sits outside authMW like the signed download:
+	// its credential is the handoff token itself, not a tenant principal.
+	e.POST("/internal/tasks/:id/status", handlers.WorkerCallbackHandler(s, handoffSigner))
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+
+	worker.RecoverInterrupted(s)
+
+	concurrency := workerConcurrency()
+	wg := workerpool.Run(rootCtx, concurrency, func(ctx context.Context) { worker.Worker(ctx, q, s, backend) })
 
 	port := os.Getenv("GATEWAY_PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	log.Printf("gateway-go starting on :%s", port)
-	if err := e.Start("0.0.0.0:" + port); err != http.ErrServerClosed {
-		log.Fatalf("server error: %v", err)
+	go func() {
+		logger.Info().Str("port", port).Int("worker_concurrency", concurrency).Msg("gateway-go starting")
+		if err := e.Start("0.0.0.0:" + port); err != nil && err != http.ErrServerClosed {
+			logger.Fatal().Err(err).Msg("server error")
+		}
+	}()
+
+	<-rootCtx.Done()
+	logger.Info().Msg("shutdown signal received, draining")
+
+	httpShutdownCtx, cancelHTTPShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelHTTPShutdown()
+	if err := e.Shutdown(httpShutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("http server shutdown")
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		logger.Info().Msg("all in-flight tasks drained cleanly")
+	case <-time.After(drainTimeout):
+		logger.Warn().Dur("timeout", drainTimeout).Msg("drain deadline exceeded, cancelling remaining tasks")
+		s.CancelAll()
+		<-drained
+	}
+}
+
+// newQueue returns a durable queue.MemoryQueue backed by a BoltPersistence
+// at dbPath, or a non-durable one if dbPath is empty.
+func newQueue(dbPath string) (queue.Queue, error) {
+	if dbPath == "" {
+		return queue.NewMemoryQueue(), nil
+	}
+	persist, err := queue.NewBoltPersistence(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	return queue.NewDurableMemoryQueue(persist)
+}
+
+// workerConcurrency reads GATEWAY_WORKER_CONCURRENCY (default 4).
+func workerConcurrency() int {
+	n, err := strconv.Atoi(os.Getenv("GATEWAY_WORKER_CONCURRENCY"))
+	if err != nil || n < 1 {
+		return 4
+	}
+	return n
+}
+
+// newAuthenticator builds the auth.Chain this deployment accepts:
+// service-to-service API keys (GATEWAY_API_KEYS, "key:tenant:user,...")
+// plus JWTs verified via GATEWAY_JWT_HS256_SECRET and/or a JWKS endpoint
+// at GATEWAY_JWT_JWKS_URL. Either credential type authenticates any
+// protected route.
+func newAuthenticator() auth.Authenticator {
+	chain := auth.Chain{}
+
+	if keys := os.Getenv("GATEWAY_API_KEYS"); keys != "" {
+		chain = append(chain, auth.NewAPIKeyAuthenticator(parseAPIKeys(keys)))
+	}
+
+	var jwtOpts []auth.JWTOption
+	if secret := os.Getenv("GATEWAY_JWT_HS256_SECRET"); secret != "" {
+		jwtOpts = append(jwtOpts, auth.WithHS256Secret([]byte(secret)))
+	}
+	if jwksURL := os.Getenv("GATEWAY_JWT_JWKS_URL"); jwksURL != "" {
+		jwtOpts = append(jwtOpts, auth.WithJWKS(jwksURL, 10*time.Minute))
+	}
+	if len(jwtOpts) > 0 {
+		chain = append(chain, auth.NewJWTAuthenticator(jwtOpts...))
+	}
+
+	return chain
+}
+
+// parseAPIKeys parses "key:tenant:user,key2:tenant2:user2" into the map
+// auth.NewAPIKeyAuthenticator expects.
+func parseAPIKeys(raw string) map[string]auth.Principal {
+	keys := make(map[string]auth.Principal)
+	for _, entry := range strings.Split(raw, ",") {
+		fields := strings.SplitN(entry, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		keys[fields[0]] = auth.Principal{TenantID: fields[1], UserID: fields[2]}
 	}
+	return keys
 }
@@ -0,0 +1,217 @@
+package artifacts
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/auth"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+)
+
+var testPrincipal = &auth.Principal{TenantID: "tenant-a", UserID: "user-a"}
+
+func withTestPrincipal(c echo.Context) echo.Context {
+	c.Set("principal", testPrincipal)
+	return c
+}
+
+func newTestBackend(t *testing.T) storage.Backend {
+	t.Helper()
+	b, err := storage.NewLocalFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local fs: %v", err)
+	}
+	return b
+}
+
+// buildFixtureArchive builds a zip in memory and stores it under
+// backend, returning the locator backend.Put returned - i.e. exactly
+// what BuildBundle itself would hand back.
+func buildFixtureArchive(t *testing.T, backend storage.Backend) string {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("report/summary.json")
+	if err != nil {
+		t.Fatalf("create entry: %v", err)
+	}
+	if _, err := w.Write([]byte(`{"ok":true}`)); err != nil {
+		t.Fatalf("write entry: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip writer: %v", err)
+	}
+
+	url, _, err := backend.Put(context.Background(), "bundle.zip", &buf)
+	if err != nil {
+		t.Fatalf("put archive: %v", err)
+	}
+	return url
+}
+
+func TestGenerateAndReadMetadataRoundTrips(t *testing.T) {
+	backend := newTestBackend(t)
+	archiveKey := buildFixtureArchive(t, backend)
+
+	metaKey, err := GenerateMetadata(context.Background(), backend, archiveKey)
+	if err != nil {
+		t.Fatalf("generate metadata: %v", err)
+	}
+
+	rc, err := backend.Open(context.Background(), metaKey)
+	if err != nil {
+		t.Fatalf("open metadata: %v", err)
+	}
+	defer rc.Close()
+
+	entries, err := ReadMetadata(rc)
+	if err != nil {
+		t.Fatalf("read metadata: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Name != "report/summary.json" {
+		t.Fatalf("unexpected entry name: %s", entries[0].Name)
+	}
+	if entries[0].Size != uint64(len(`{"ok":true}`)) {
+		t.Fatalf("unexpected entry size: %d", entries[0].Size)
+	}
+}
+
+func TestMetadataHandlerGeneratesSidecarOnFirstRequest(t *testing.T) {
+	backend := newTestBackend(t)
+	archiveKey := buildFixtureArchive(t, backend)
+	s := store.NewStore()
+	if err := s.AddTask(&store.Task{ID: "task-1", OwnerID: testPrincipal.TenantID, Status: "Completed", ArchivePath: archiveKey}); err != nil {
+		t.Fatalf("add task: %v", err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task-1/artifacts/metadata", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("task-1")
+
+	if err := MetadataHandler(s, backend)(withTestPrincipal(ctx)); err != nil {
+		t.Fatalf("metadata handler error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if _, err := backend.Stat(context.Background(), archiveKey+".meta"); err != nil {
+		t.Fatalf("expected sidecar to be generated: %v", err)
+	}
+}
+
+func TestExtractHandlerStreamsEntry(t *testing.T) {
+	backend := newTestBackend(t)
+	archiveKey := buildFixtureArchive(t, backend)
+	s := store.NewStore()
+	if err := s.AddTask(&store.Task{ID: "task-2", OwnerID: testPrincipal.TenantID, Status: "Completed", ArchivePath: archiveKey}); err != nil {
+		t.Fatalf("add task: %v", err)
+	}
+
+	entryToken := base64.URLEncoding.EncodeToString([]byte("report/summary.json"))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task-2/artifacts/file/"+entryToken, nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id", "entry")
+	ctx.SetParamValues("task-2", entryToken)
+
+	if err := ExtractHandler(s, backend)(withTestPrincipal(ctx)); err != nil {
+		t.Fatalf("extract handler error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if got := rec.Header().Get(echo.HeaderContentType); got != "application/json" {
+		t.Fatalf("expected application/json content type, got %s", got)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), []byte(`{"ok":true}`)) {
+		t.Fatalf("unexpected body: %s", rec.Body.Bytes())
+	}
+}
+
+func TestBuildBundleRoundTrips(t *testing.T) {
+	backend := newTestBackend(t)
+	dir := t.TempDir()
+	annotated := filepath.Join(dir, "task-1-annotated.docx")
+	if err := os.WriteFile(annotated, []byte("annotated content"), 0o644); err != nil {
+		t.Fatalf("write annotated fixture: %v", err)
+	}
+	reportPath := filepath.Join(dir, "task-1-report.json")
+	if err := os.WriteFile(reportPath, []byte(`{"issues":[]}`), 0o644); err != nil {
+		t.Fatalf("write report fixture: %v", err)
+	}
+
+	archiveKey, err := BuildBundle(context.Background(), backend, "task-1-bundle.zip", map[string]string{
+		"task-1-annotated.docx": annotated,
+		"task-1-report.json":    reportPath,
+	})
+	if err != nil {
+		t.Fatalf("build bundle: %v", err)
+	}
+
+	rc, err := backend.Open(context.Background(), archiveKey)
+	if err != nil {
+		t.Fatalf("open bundle: %v", err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read bundle for zip.NewReader: %v", err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open bundle as zip: %v", err)
+	}
+	if len(r.File) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(r.File))
+	}
+
+	for _, fe := range r.File {
+		if fe.Name != "task-1-annotated.docx" && fe.Name != "task-1-report.json" {
+			t.Fatalf("unexpected entry name: %s", fe.Name)
+		}
+	}
+}
+
+func TestExtractHandlerUnknownEntryReturns404(t *testing.T) {
+	backend := newTestBackend(t)
+	archiveKey := buildFixtureArchive(t, backend)
+	s := store.NewStore()
+	if err := s.AddTask(&store.Task{ID: "task-3", OwnerID: testPrincipal.TenantID, Status: "Completed", ArchivePath: archiveKey}); err != nil {
+		t.Fatalf("add task: %v", err)
+	}
+
+	entryToken := base64.URLEncoding.EncodeToString([]byte("missing.txt"))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/task-3/artifacts/file/"+entryToken, nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id", "entry")
+	ctx.SetParamValues("task-3", entryToken)
+
+	if err := ExtractHandler(s, backend)(withTestPrincipal(ctx)); err != nil {
+		t.Fatalf("extract handler error: %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,53 @@
+package artifacts
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"os"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+)
+
+// BuildBundle zips the given files into a fresh archive and stores it
+// under bundleKey via backend, returning the backend-managed locator
+// backend.Put returns (a local path for LocalFS, an s3:// or gs:// URL
+// otherwise) so it round-trips through GenerateMetadata/MetadataHandler/
+// ExtractHandler exactly like Task.SourcePath does. The archive is
+// built in memory rather than streamed straight to backend, since
+// archive/zip needs to seek back and rewrite its central directory on
+// Close, which storage.Backend's write-only Put can't support.
+func BuildBundle(ctx context.Context, backend storage.Backend, bundleKey string, entries map[string]string) (string, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, srcPath := range entries {
+		if err := addFile(zw, name, srcPath); err != nil {
+			zw.Close()
+			return "", err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	url, _, err := backend.Put(ctx, bundleKey, &buf)
+	return url, err
+}
+
+// addFile still reads srcPath off local disk: it's the annotated docx
+// and report.json a worker just wrote to its own scratch directory, not
+// a backend-resident object.
+func addFile(zw *zip.Writer, name, srcPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
@@ -0,0 +1,135 @@
+package artifacts
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/auth"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+)
+
+// MetadataHandler serves the gzip-compressed sidecar index for a task's
+// zipped result bundle, generating it on first request if it doesn't
+// exist yet. It takes a Backend so it can read/write Task.ArchivePath
+// exactly like BuildBundle/GenerateMetadata do, regardless of which
+// storage.Config.Driver the deployment runs.
+func MetadataHandler(s *store.Store, backend storage.Backend) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
+		id := c.Param("id")
+		t, ok := s.GetTaskForOwner(id, principal.TenantID)
+		if !ok {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "task not found"})
+		}
+		if t.ArchivePath == "" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "no artifact bundle for this task"})
+		}
+
+		ctx := c.Request().Context()
+		metaKey := t.ArchivePath + ".meta"
+		if _, err := backend.Stat(ctx, metaKey); err != nil {
+			if !errors.Is(err, storage.ErrNotExist) {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to stat archive index"})
+			}
+			if _, err := GenerateMetadata(ctx, backend, t.ArchivePath); err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to index archive"})
+			}
+		}
+
+		rc, err := backend.Open(ctx, metaKey)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to open archive index"})
+		}
+		defer rc.Close()
+
+		c.Response().Header().Set(echo.HeaderContentType, "application/gzip")
+		c.Response().WriteHeader(http.StatusOK)
+		_, err = io.Copy(c.Response(), rc)
+		return err
+	}
+}
+
+// ExtractHandler streams a single entry out of a task's zipped result
+// bundle. :entry is base64-encoded so it can carry the archive's
+// internal path, which may contain slashes, as one URL path segment.
+func ExtractHandler(s *store.Store, backend storage.Backend) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
+		id := c.Param("id")
+		t, ok := s.GetTaskForOwner(id, principal.TenantID)
+		if !ok {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "task not found"})
+		}
+		if t.ArchivePath == "" {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "no artifact bundle for this task"})
+		}
+
+		nameBytes, err := base64.URLEncoding.DecodeString(c.Param("entry"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "entry must be base64-encoded"})
+		}
+		entryName := string(nameBytes)
+
+		ctx := c.Request().Context()
+		rc, err := backend.Open(ctx, t.ArchivePath)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "cannot open archive"})
+		}
+		// zip.NewReader needs an io.ReaderAt to seek to the central
+		// directory, which storage.Backend's io.ReadCloser-only Open
+		// can't provide, so the archive is read fully into memory.
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "cannot read archive"})
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "cannot open archive"})
+		}
+
+		var fe *zip.File
+		for _, candidate := range zr.File {
+			if candidate.Name == entryName {
+				fe = candidate
+				break
+			}
+		}
+		if fe == nil {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "entry not found"})
+		}
+
+		entryR, err := fe.Open()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "cannot open entry"})
+		}
+		defer entryR.Close()
+
+		contentType := mime.TypeByExtension(filepath.Ext(fe.Name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		c.Response().Header().Set(echo.HeaderContentType, contentType)
+		c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatUint(fe.UncompressedSize64, 10))
+		c.Response().WriteHeader(http.StatusOK)
+		_, err = io.Copy(c.Response(), entryR)
+		return err
+	}
+}
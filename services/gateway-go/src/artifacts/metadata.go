@@ -0,0 +1,175 @@
+// Package artifacts generates and serves a lightweight sidecar index for
+// zipped audit-result bundles, so a client can list or fetch one file
+// inside an archive without downloading the whole thing.
+package artifacts
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+)
+
+// metaMagic identifies a .zip.meta sidecar; the last byte is the format
+// version, bumped whenever the record layout below changes.
+var metaMagic = [4]byte{'Z', 'M', 'T', 1}
+
+// EntryMeta is one zip entry's sidecar record.
+type EntryMeta struct {
+	Name    string
+	ModTime time.Time
+	Mode    os.FileMode
+	Size    uint64
+	CRC32   uint32
+	Comment string
+}
+
+// GenerateMetadata reads archiveKey's central directory and writes a
+// gzip-compressed sidecar at archiveKey+".meta" via backend: a
+// header/version followed by one length-prefixed record per entry
+// (path, modtime, mode, size, CRC-32, comment), so MetadataHandler can
+// serve a listing without holding the archive open per request.
+//
+// archive/zip.NewReader needs an io.ReaderAt to seek to the central
+// directory at the end of the file, which storage.Backend's
+// io.ReadCloser-only Open can't provide, so the archive is read fully
+// into memory first.
+func GenerateMetadata(ctx context.Context, backend storage.Backend, archiveKey string) (string, error) {
+	rc, err := backend.Open(ctx, archiveKey)
+	if err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return "", err
+	}
+
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(metaMagic[:]); err != nil {
+		return "", err
+	}
+	for _, fe := range r.File {
+		if err := writeEntry(gz, fe); err != nil {
+			return "", err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return "", err
+	}
+
+	metaKey := archiveKey + ".meta"
+	url, _, err := backend.Put(ctx, metaKey, &buf)
+	return url, err
+}
+
+func writeEntry(w io.Writer, fe *zip.File) error {
+	if err := writeLenPrefixed(w, []byte(fe.Name)); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, fe.Modified.UnixNano()); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(fe.Mode())); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, fe.UncompressedSize64); err != nil {
+		return err
+	}
+	if err := binary.Write(w, binary.BigEndian, fe.CRC32); err != nil {
+		return err
+	}
+	return writeLenPrefixed(w, []byte(fe.Comment))
+}
+
+func writeLenPrefixed(w io.Writer, b []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// ReadMetadata parses a sidecar written by GenerateMetadata.
+func ReadMetadata(r io.Reader) ([]EntryMeta, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	var magic [4]byte
+	if _, err := io.ReadFull(gz, magic[:]); err != nil {
+		return nil, err
+	}
+	if magic != metaMagic {
+		return nil, fmt.Errorf("artifacts: unrecognized sidecar header %x", magic)
+	}
+
+	var entries []EntryMeta
+	for {
+		name, err := readLenPrefixed(gz)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var modNano int64
+		if err := binary.Read(gz, binary.BigEndian, &modNano); err != nil {
+			return nil, err
+		}
+		var mode uint32
+		if err := binary.Read(gz, binary.BigEndian, &mode); err != nil {
+			return nil, err
+		}
+		var size uint64
+		if err := binary.Read(gz, binary.BigEndian, &size); err != nil {
+			return nil, err
+		}
+		var crc uint32
+		if err := binary.Read(gz, binary.BigEndian, &crc); err != nil {
+			return nil, err
+		}
+		comment, err := readLenPrefixed(gz)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, EntryMeta{
+			Name:    string(name),
+			ModTime: time.Unix(0, modNano),
+			Mode:    os.FileMode(mode),
+			Size:    size,
+			CRC32:   crc,
+			Comment: string(comment),
+		})
+	}
+	return entries, nil
+}
+
+func readLenPrefixed(r io.Reader) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"net/http"
+)
+
+// APIKeyAuthenticator authenticates requests carrying an
+// "X-API-Key: <key>" header against a static map of key -> Principal,
+// suitable for service-to-service callers that don't go through OIDC.
+type APIKeyAuthenticator struct {
+	keys map[string]Principal
+}
+
+// NewAPIKeyAuthenticator builds an authenticator over the given
+// key-to-principal mapping, typically loaded from an env var or config
+// file at startup.
+func NewAPIKeyAuthenticator(keys map[string]Principal) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{keys: keys}
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get("X-API-Key")
+	if key == "" {
+		return nil, ErrUnauthenticated
+	}
+	p, ok := a.keys[key]
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return &p, nil
+}
@@ -0,0 +1,73 @@
+// Package auth authenticates inbound HTTP requests and attaches a
+// Principal (who, and which tenant) to the echo context, so handlers can
+// enforce per-tenant isolation on every task read/write instead of
+// trusting a caller-supplied UUID.
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Principal identifies the caller a request was authenticated as.
+type Principal struct {
+	UserID   string
+	TenantID string
+}
+
+// Authenticator validates a request and returns the Principal it
+// authenticates as. APIKeyAuthenticator and JWTAuthenticator are the two
+// implementations; Chain lets both be accepted on the same route.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// ErrUnauthenticated is returned by an Authenticator when the request
+// carries no usable credential, as opposed to a credential that parsed
+// but failed verification.
+var ErrUnauthenticated = errors.New("auth: no credential presented")
+
+type principalKey struct{}
+
+// Chain tries each Authenticator in order and returns the first
+// successful Principal, or the last error if every one fails. This lets
+// a deployment accept either an API key or a bearer JWT on the same
+// route without the handler caring which was used.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(r *http.Request) (*Principal, error) {
+	var lastErr error = ErrUnauthenticated
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if err == nil {
+			return p, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// Middleware authenticates every request via auth and, on success,
+// attaches the resulting Principal to the context for handlers to read
+// via FromContext. Requests that fail authentication get a 401 before
+// reaching the handler.
+func Middleware(a Authenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			p, err := a.Authenticate(c.Request())
+			if err != nil {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+			}
+			c.Set("principal", p)
+			return next(c)
+		}
+	}
+}
+
+// FromContext returns the Principal attached by Middleware, if any.
+func FromContext(c echo.Context) (*Principal, bool) {
+	p, ok := c.Get("principal").(*Principal)
+	return p, ok
+}
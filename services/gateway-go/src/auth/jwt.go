@@ -0,0 +1,244 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksFetchTimeout bounds how long a JWKS refresh can block, mirroring
+// the 10-second timeout upload.Config/preAuthorize uses for its own
+// backend call. Without it, a slow/unresponsive JWKS endpoint would hang
+// refresh() indefinitely instead of letting jwksCache.key fall back to a
+// stale key.
+const jwksFetchTimeout = 10 * time.Second
+
+// JWTAuthenticator validates a bearer token from the Authorization
+// header, accepting either a static HS256 secret (for trusted internal
+// callers) or RS256 tokens signed by a key published at a JWKS endpoint
+// (for OIDC-issued tokens). TenantID/UserID are read from configurable
+// claim names since different identity providers name them differently.
+type JWTAuthenticator struct {
+	hs256Secret []byte
+	jwks        *jwksCache
+
+	tenantClaim string
+	userClaim   string
+}
+
+// JWTOption configures a JWTAuthenticator.
+type JWTOption func(*JWTAuthenticator)
+
+// WithHS256Secret enables verifying HS256-signed tokens against secret,
+// e.g. for tokens minted by this same gateway's own internal callers.
+func WithHS256Secret(secret []byte) JWTOption {
+	return func(a *JWTAuthenticator) { a.hs256Secret = secret }
+}
+
+// WithJWKS enables verifying RS256-signed tokens against the keys
+// published at jwksURL, refreshed every refreshInterval.
+func WithJWKS(jwksURL string, refreshInterval time.Duration) JWTOption {
+	return func(a *JWTAuthenticator) { a.jwks = newJWKSCache(jwksURL, refreshInterval) }
+}
+
+// WithClaimNames overrides the default "tenant_id"/"sub" claim names
+// used to populate Principal.TenantID/UserID.
+func WithClaimNames(tenantClaim, userClaim string) JWTOption {
+	return func(a *JWTAuthenticator) {
+		a.tenantClaim = tenantClaim
+		a.userClaim = userClaim
+	}
+}
+
+// NewJWTAuthenticator builds a JWTAuthenticator from the given options.
+// At least one of WithHS256Secret or WithJWKS must be supplied or every
+// token will fail to verify.
+func NewJWTAuthenticator(opts ...JWTOption) *JWTAuthenticator {
+	a := &JWTAuthenticator{tenantClaim: "tenant_id", userClaim: "sub"}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, ErrUnauthenticated
+	}
+	raw := strings.TrimPrefix(header, "Bearer ")
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, a.keyFunc)
+	if err != nil {
+		return nil, fmt.Errorf("auth: invalid token: %w", err)
+	}
+
+	tenantID, _ := claims[a.tenantClaim].(string)
+	userID, _ := claims[a.userClaim].(string)
+	if userID == "" {
+		return nil, fmt.Errorf("auth: token missing %q claim", a.userClaim)
+	}
+	return &Principal{UserID: userID, TenantID: tenantID}, nil
+}
+
+func (a *JWTAuthenticator) keyFunc(token *jwt.Token) (any, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if a.hs256Secret == nil {
+			return nil, fmt.Errorf("auth: HS256 not configured")
+		}
+		return a.hs256Secret, nil
+	case *jwt.SigningMethodRSA:
+		if a.jwks == nil {
+			return nil, fmt.Errorf("auth: RS256/JWKS not configured")
+		}
+		kid, _ := token.Header["kid"].(string)
+		return a.jwks.key(kid)
+	default:
+		return nil, fmt.Errorf("auth: unsupported signing method %v", token.Header["alg"])
+	}
+}
+
+// jwksCache fetches and periodically refreshes a JWKS document, caching
+// the parsed public keys by kid so every request doesn't pay a network
+// round trip to the identity provider.
+type jwksCache struct {
+	url      string
+	interval time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]any
+	lastFetch time.Time
+
+	fetch func(url string) (map[string]any, error)
+}
+
+func newJWKSCache(url string, interval time.Duration) *jwksCache {
+	return &jwksCache{
+		url:      url,
+		interval: interval,
+		keys:     make(map[string]any),
+		fetch:    fetchJWKS,
+	}
+}
+
+func (c *jwksCache) key(kid string) (any, error) {
+	c.mu.RLock()
+	stale := time.Since(c.lastFetch) > c.interval
+	k, ok := c.keys[kid]
+	c.mu.RUnlock()
+
+	if ok && !stale {
+		return k, nil
+	}
+
+	if err := c.refresh(); err != nil {
+		if ok {
+			// Serve the stale key rather than fail auth outright on a
+			// transient JWKS-endpoint blip.
+			return k, nil
+		}
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	k, ok = c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("auth: unknown signing key %q", kid)
+	}
+	return k, nil
+}
+
+func (c *jwksCache) refresh() error {
+	keys, err := c.fetch(c.url)
+	if err != nil {
+		return fmt.Errorf("auth: fetch JWKS: %w", err)
+	}
+	c.mu.Lock()
+	c.keys = keys
+	c.lastFetch = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+// jwksDocument is the standard JWK Set document shape (RFC 7517).
+type jwksDocument struct {
+	Keys []jwkKey `json:"keys"`
+}
+
+// jwkKey is one RSA public key entry; kty/n/e are the only fields an
+// RS256-only verifier needs.
+type jwkKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// fetchJWKS is overridden in tests; in production it fetches the JWKS
+// document at url and parses its RSA keys into a kid -> *rsa.PublicKey
+// map. Non-RSA entries (e.g. "kty":"EC") are skipped since keyFunc only
+// ever asks this cache to verify jwt.SigningMethodRSA tokens.
+var fetchJWKS = func(url string) (map[string]any, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("JWKS endpoint %q returned %s", url, resp.Status)
+	}
+
+	var doc jwksDocument
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode JWKS document: %w", err)
+	}
+
+	keys := make(map[string]any, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		pub, err := rsaPublicKeyFromJWK(k)
+		if err != nil {
+			return nil, fmt.Errorf("parse JWKS key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = pub
+	}
+	return keys, nil
+}
+
+// rsaPublicKeyFromJWK decodes a JWK's base64url-encoded modulus (n) and
+// exponent (e) into an *rsa.PublicKey.
+func rsaPublicKeyFromJWK(k jwkKey) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("decode modulus: %w", err)
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("decode exponent: %w", err)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWTAuthenticatorHS256(t *testing.T) {
+	secret := []byte("test-secret")
+	a := NewJWTAuthenticator(WithHS256Secret(secret))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":       "user-a",
+		"tenant_id": "tenant-a",
+	})
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+
+	p, err := a.Authenticate(req)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if p.UserID != "user-a" || p.TenantID != "tenant-a" {
+		t.Fatalf("unexpected principal: %+v", p)
+	}
+}
+
+func TestJWTAuthenticatorRejectsBadSecret(t *testing.T) {
+	a := NewJWTAuthenticator(WithHS256Secret([]byte("right-secret")))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "user-a"})
+	signed, err := token.SignedString([]byte("wrong-secret"))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+
+	if _, err := a.Authenticate(req); err == nil {
+		t.Fatalf("expected authentication to fail with mismatched secret")
+	}
+}
+
+func TestJWTAuthenticatorJWKS(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"keys":[{"kty":"RSA","kid":"key-1","n":"` +
+			base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()) +
+			`","e":"` + base64.RawURLEncoding.EncodeToString(bigEndianExponent(key.PublicKey.E)) + `"}]}`))
+	}))
+	defer jwks.Close()
+
+	a := NewJWTAuthenticator(WithJWKS(jwks.URL, time.Minute))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"sub":       "user-b",
+		"tenant_id": "tenant-b",
+	})
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+
+	p, err := a.Authenticate(req)
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	if p.UserID != "user-b" || p.TenantID != "tenant-b" {
+		t.Fatalf("unexpected principal: %+v", p)
+	}
+}
+
+func TestJWTAuthenticatorRejectsUnknownKid(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer jwks.Close()
+
+	a := NewJWTAuthenticator(WithJWKS(jwks.URL, time.Minute))
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"sub": "user-b"})
+	token.Header["kid"] = "missing-key"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+signed)
+
+	if _, err := a.Authenticate(req); err == nil {
+		t.Fatalf("expected authentication to fail for unknown kid")
+	}
+}
+
+// TestFetchJWKSTimesOutOnSlowEndpoint guards against a regression to a
+// bare http.Get: a JWKS endpoint that never responds must not hang the
+// refresh call indefinitely, it should return an error (so jwksCache.key
+// can fall back to a stale key) well before a human would notice.
+func TestFetchJWKSTimesOutOnSlowEndpoint(t *testing.T) {
+	blockUntilClosed := make(chan struct{})
+	t.Cleanup(func() { close(blockUntilClosed) })
+
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-blockUntilClosed
+	}))
+	defer jwks.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := fetchJWKS(jwks.URL)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatalf("expected fetchJWKS to fail against a hung endpoint")
+		}
+	case <-time.After(15 * time.Second):
+		t.Fatalf("fetchJWKS did not return within the bounded timeout")
+	}
+}
+
+// bigEndianExponent mirrors the encoding/binary big-endian trim that
+// rsaPublicKeyFromJWK expects on decode: the smallest big-endian byte
+// slice representing e, with no leading zero byte.
+func bigEndianExponent(e int) []byte {
+	b := make([]byte, 4)
+	b[0] = byte(e >> 24)
+	b[1] = byte(e >> 16)
+	b[2] = byte(e >> 8)
+	b[3] = byte(e)
+	i := 0
+	for i < len(b)-1 && b[i] == 0 {
+		i++
+	}
+	return b[i:]
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestMiddlewareRejectsMissingCredential(t *testing.T) {
+	e := echo.New()
+	authenticator := NewAPIKeyAuthenticator(map[string]Principal{"good-key": {TenantID: "t1", UserID: "u1"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := Middleware(authenticator)(func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	if err := handler(c); err != nil {
+		t.Fatalf("middleware error: %v", err)
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestMiddlewareAttachesPrincipalOnSuccess(t *testing.T) {
+	e := echo.New()
+	authenticator := NewAPIKeyAuthenticator(map[string]Principal{"good-key": {TenantID: "t1", UserID: "u1"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", "good-key")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var gotPrincipal *Principal
+	handler := Middleware(authenticator)(func(c echo.Context) error {
+		p, _ := FromContext(c)
+		gotPrincipal = p
+		return c.NoContent(http.StatusOK)
+	})
+	if err := handler(c); err != nil {
+		t.Fatalf("middleware error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if gotPrincipal == nil || gotPrincipal.TenantID != "t1" {
+		t.Fatalf("expected tenant t1 attached to context, got %+v", gotPrincipal)
+	}
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// TenantRateLimiter hands out one token-bucket limiter per tenant so a
+// single noisy tenant can't starve the others out of shared gateway
+// capacity. Limiters are created lazily on first use and never evicted;
+// that's fine at the tenant counts this gateway expects, but would need
+// an LRU if tenant churn became high.
+type TenantRateLimiter struct {
+	rps   rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewTenantRateLimiter builds a limiter allowing rps requests/sec with
+// bursts up to burst, per tenant.
+func NewTenantRateLimiter(rps float64, burst int) *TenantRateLimiter {
+	return &TenantRateLimiter{
+		rps:      rate.Limit(rps),
+		burst:    burst,
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+func (t *TenantRateLimiter) limiterFor(tenantID string) *rate.Limiter {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	l, ok := t.limiters[tenantID]
+	if !ok {
+		l = rate.NewLimiter(t.rps, t.burst)
+		t.limiters[tenantID] = l
+	}
+	return l
+}
+
+// Middleware rejects a request with 429 once its tenant has exceeded its
+// rate. It must run after Middleware(authenticator) so a Principal is
+// already attached to the context; requests with no principal are exempt
+// since they carry no tenant to key on.
+func (t *TenantRateLimiter) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			p, ok := FromContext(c)
+			if !ok {
+				return next(c)
+			}
+			if !t.limiterFor(p.TenantID).Allow() {
+				return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "rate limit exceeded"})
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestURLSignerRoundTrip(t *testing.T) {
+	signer := NewURLSigner([]byte("test-secret"))
+	token := signer.Sign("task-1", "/tmp/report.json", time.Minute)
+
+	taskID, path, ok := signer.Verify(token)
+	if !ok {
+		t.Fatalf("expected token to verify")
+	}
+	if taskID != "task-1" || path != "/tmp/report.json" {
+		t.Fatalf("unexpected payload: taskID=%s path=%s", taskID, path)
+	}
+}
+
+func TestURLSignerRejectsExpiredToken(t *testing.T) {
+	signer := NewURLSigner([]byte("test-secret"))
+	token := signer.Sign("task-1", "/tmp/report.json", -time.Minute)
+
+	if _, _, ok := signer.Verify(token); ok {
+		t.Fatalf("expected an expired token to fail verification")
+	}
+}
+
+func TestURLSignerRejectsTamperedToken(t *testing.T) {
+	signerA := NewURLSigner([]byte("secret-a"))
+	signerB := NewURLSigner([]byte("secret-b"))
+	token := signerA.Sign("task-1", "/tmp/report.json", time.Minute)
+
+	if _, _, ok := signerB.Verify(token); ok {
+		t.Fatalf("expected a token signed with a different secret to fail verification")
+	}
+}
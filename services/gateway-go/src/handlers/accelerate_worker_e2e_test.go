@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/queue"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/upload"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/worker"
+)
+
+// TestAcceleratedUploadIsConsumableByWorker is the end-to-end check the
+// chunk1-1 review asked for: with PreAuthURL configured (so
+// upload.Accelerate actually runs, not its no-op pass-through), an
+// uploaded task's SourcePath must be a locator a worker can actually
+// open — proving Accelerate and UploadHandler agree on the same
+// Backend-managed locator format instead of one handing the other a
+// bare local temp path or object-store URL it can't Open/Stat.
+//
+// It runs against both LocalFS and fakeBackend (see
+// fake_backend_test.go), which returns "fake://..." locators that
+// os.Open would reject outright: LocalFS alone would pass even if the
+// worker read SourcePath via a raw os.Open that happened to work
+// because LocalFS's locator is coincidentally a real disk path, the
+// exact regression this test exists to catch.
+func TestAcceleratedUploadIsConsumableByWorker(t *testing.T) {
+	backends := []struct {
+		name    string
+		backend storage.Backend
+	}{
+		{name: "local", backend: newLocalFSBackend(t)},
+		{name: "fake", backend: newFakeBackend()},
+	}
+
+	for _, tt := range backends {
+		t.Run(tt.name, func(t *testing.T) {
+			testAcceleratedUploadIsConsumableByWorker(t, tt.backend)
+		})
+	}
+}
+
+func newLocalFSBackend(t *testing.T) storage.Backend {
+	t.Helper()
+	backend, err := storage.NewLocalFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local fs: %v", err)
+	}
+	return backend
+}
+
+func testAcceleratedUploadIsConsumableByWorker(t *testing.T, backend storage.Backend) {
+	e := echo.New()
+	s := store.NewStore()
+	q := queue.NewMemoryQueue()
+
+	preAuth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer preAuth.Close()
+
+	accel := upload.Accelerate(upload.Config{PreAuthURL: preAuth.URL, Backend: backend}, "file")
+	uploadHandler := UploadHandler(s, q, testHandoffSigner, backend)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	fw, err := writer.CreateFormFile("file", "sample.docx")
+	if err != nil {
+		t.Fatalf("create form file: %v", err)
+	}
+	if _, err := fw.Write([]byte("accelerated upload content")); err != nil {
+		t.Fatalf("write form file: %v", err)
+	}
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/upload", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	if err := accel(uploadHandler)(withTestPrincipal(e.NewContext(req, rec))); err != nil {
+		t.Fatalf("accelerated upload error: %v", err)
+	}
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("parse response: %v", err)
+	}
+	taskID := resp["task_id"]
+	if taskID == "" {
+		t.Fatalf("task_id empty")
+	}
+
+	// local_worker.go writes its annotated copy under a fixed
+	// "../temp_docs" relative to the process's working directory; make
+	// sure it exists so a missing-directory write failure (which
+	// processTask swallows) can't masquerade as success below.
+	tempDocsDir := filepath.Join("..", "temp_docs")
+	if err := os.MkdirAll(tempDocsDir, 0o755); err != nil {
+		t.Fatalf("mkdir temp_docs: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.Remove(filepath.Join(tempDocsDir, taskID+"-annotated.docx"))
+		_ = os.Remove(filepath.Join(tempDocsDir, taskID+"-report.json"))
+		_ = os.Remove(filepath.Join(tempDocsDir, taskID+"-bundle.zip"))
+	})
+
+	workerCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		worker.Worker(workerCtx, q, s, backend)
+		close(done)
+	}()
+
+	// The simulated worker alone sleeps 5s (1s parse plus 5 x 800ms audit).
+	deadline := time.After(10 * time.Second)
+	for {
+		tsk, ok := s.GetTask(taskID)
+		if ok && tsk.Status != "Queued" && tsk.Status != "Parsing" && tsk.Status != "Auditing" {
+			cancel()
+			<-done
+			if tsk.Status != "Completed" {
+				t.Fatalf("expected task to complete, got status %q (last error: %s)", tsk.Status, tsk.LastError)
+			}
+			if tsk.AnnotatedPath == "" {
+				t.Fatalf("expected worker to have produced an annotated copy from SourcePath")
+			}
+			got, err := os.ReadFile(tsk.AnnotatedPath)
+			if err != nil {
+				t.Fatalf("worker never actually read SourcePath via the backend-returned locator: %v", err)
+			}
+			if string(got) != "accelerated upload content" {
+				t.Fatalf("annotated copy content mismatch: %q", got)
+			}
+			if tsk.ArchivePath == "" {
+				t.Fatalf("expected worker to have built an artifact bundle")
+			}
+			return
+		}
+		select {
+		case <-deadline:
+			cancel()
+			<-done
+			t.Fatalf("task never reached a terminal status in time; last status %q", tsk.Status)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
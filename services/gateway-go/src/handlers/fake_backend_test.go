@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/queue"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+)
+
+// fakeBackend is an in-memory storage.Backend with no filesystem
+// involvement at all, used to prove UploadHandler/StatusHandler go
+// through Backend exclusively rather than reaching for os.Open/os.Stat
+// on the side.
+type fakeBackend struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{objects: make(map[string][]byte)}
+}
+
+func (b *fakeBackend) Put(_ context.Context, key string, r io.Reader) (string, int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", 0, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = data
+	return "fake://" + key, int64(len(data)), nil
+}
+
+func (b *fakeBackend) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	name, ok := b.keyFromURL(key)
+	if !ok {
+		return nil, storage.ErrNotExist
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.objects[name]
+	if !ok {
+		return nil, storage.ErrNotExist
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (b *fakeBackend) Delete(_ context.Context, key string) error {
+	name, ok := b.keyFromURL(key)
+	if !ok {
+		return storage.ErrNotExist
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.objects[name]; !ok {
+		return storage.ErrNotExist
+	}
+	delete(b.objects, name)
+	return nil
+}
+
+func (b *fakeBackend) Stat(_ context.Context, key string) (storage.Stat, error) {
+	name, ok := b.keyFromURL(key)
+	if !ok {
+		return storage.Stat{}, storage.ErrNotExist
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	data, ok := b.objects[name]
+	if !ok {
+		return storage.Stat{}, storage.ErrNotExist
+	}
+	return storage.Stat{Size: int64(len(data))}, nil
+}
+
+func (b *fakeBackend) keyFromURL(url string) (string, bool) {
+	const prefix = "fake://"
+	if len(url) <= len(prefix) || url[:len(prefix)] != prefix {
+		return "", false
+	}
+	return url[len(prefix):], true
+}
+
+// TestUploadAndStatusHandlersUseBackendOnly runs UploadHandler and
+// StatusHandler against fakeBackend, which holds everything in memory
+// and would return storage.ErrNotExist (or simply never see the data)
+// if either handler tried to read or stat a local path instead of going
+// through Backend. That proves the fallback upload path never touches
+// os directly, for file contents of varying size.
+func TestUploadAndStatusHandlersUseBackendOnly(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "small file", content: []byte("hello")},
+		{name: "empty file", content: []byte{}},
+		{name: "larger file", content: bytes.Repeat([]byte("ai-auditor "), 1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			s := store.NewStore()
+			q := queue.NewMemoryQueue()
+			backend := newFakeBackend()
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			fw, err := writer.CreateFormFile("file", "sample.docx")
+			if err != nil {
+				t.Fatalf("create form file: %v", err)
+			}
+			if _, err := fw.Write(tt.content); err != nil {
+				t.Fatalf("write form file: %v", err)
+			}
+			writer.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/upload", &body)
+			req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+			rec := httptest.NewRecorder()
+			if err := UploadHandler(s, q, testHandoffSigner, backend)(withTestPrincipal(e.NewContext(req, rec))); err != nil {
+				t.Fatalf("upload handler error: %v", err)
+			}
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("expected 202, got %d", rec.Code)
+			}
+
+			var resp map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("parse response: %v", err)
+			}
+			id := resp["task_id"]
+			if id == "" {
+				t.Fatalf("task_id empty")
+			}
+
+			if len(backend.objects) != 1 {
+				t.Fatalf("expected exactly one object in backend, got %d", len(backend.objects))
+			}
+
+			req2 := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/"+id, nil)
+			rec2 := httptest.NewRecorder()
+			ctx2 := e.NewContext(req2, rec2)
+			ctx2.SetParamNames("id")
+			ctx2.SetParamValues(id)
+			if err := StatusHandler(s, backend)(withTestPrincipal(ctx2)); err != nil {
+				t.Fatalf("status handler error: %v", err)
+			}
+			if rec2.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", rec2.Code)
+			}
+
+			var status statusResponse
+			if err := json.Unmarshal(rec2.Body.Bytes(), &status); err != nil {
+				t.Fatalf("parse status response: %v", err)
+			}
+			if status.SourceSize == nil || *status.SourceSize != int64(len(tt.content)) {
+				t.Fatalf("expected source_size %d, got %v", len(tt.content), status.SourceSize)
+			}
+		})
+	}
+}
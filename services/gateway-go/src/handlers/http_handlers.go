@@ -1,77 +1,179 @@
 package handlers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/auth"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/queue"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/report"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/secret"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
 	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/upload"
 )
 
-// UploadHandler handles file uploads and enqueues tasks.
-func UploadHandler(s *store.Store, tasks chan<- string) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		f, err := c.FormFile("file")
-		if err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": "file required"})
-		}
+const signedDownloadTTL = 5 * time.Minute
 
-		src, err := f.Open()
-		if err != nil {
-			return err
+// UploadHandler handles file uploads and enqueues tasks. Repeated
+// uploads of the same file content (by SHA-256) are deduplicated onto
+// whichever task already exists for that hash, rather than kicking off
+// a redundant audit. It never touches the filesystem itself: the
+// fallback (non-accelerated) path writes through backend, so gateway
+// replicas that don't share a local disk can still serve uploads as
+// long as backend points them at the same store.
+func UploadHandler(s *store.Store, q queue.Queue, handoff *secret.HandoffSigner, backend storage.Backend) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
 		}
-		defer src.Close()
 
 		id := uuid.New().String()
-		tmpDir := filepath.Join("..", "temp_docs")
-		_ = os.MkdirAll(tmpDir, 0o755)
-		dstPath := filepath.Join(tmpDir, id+".docx")
-		dst, err := os.Create(dstPath)
+
+		// If upload.Accelerate already streamed the file into backend,
+		// skip buffering it again and reuse its hashes and locator: it
+		// went through the same Backend.Put this fallback branch below
+		// calls directly, so dstPath is backend-managed either way.
+		var dstPath, sha string
+		var cleanup func()
+		if fh, ok := upload.FromContext(c, "file"); ok {
+			dstPath = fh.SourcePath
+			sha = fh.SHA256
+			cleanup = func() { _ = backend.Delete(c.Request().Context(), dstPath) }
+		} else {
+			f, err := c.FormFile("file")
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": "file required"})
+			}
+
+			src, err := f.Open()
+			if err != nil {
+				return err
+			}
+			defer src.Close()
+
+			hasher := sha256.New()
+			url, _, err := backend.Put(c.Request().Context(), id+".docx", io.TeeReader(src, hasher))
+			if err != nil {
+				return err
+			}
+			dstPath = url
+			sha = hex.EncodeToString(hasher.Sum(nil))
+			cleanup = func() { _ = backend.Delete(c.Request().Context(), dstPath) }
+		}
+		taskID, deduped, err := enqueueUpload(s, q, handoff, principal, id, dstPath, sha)
 		if err != nil {
-			return err
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 		}
-		defer dst.Close()
-
-		if _, err := io.Copy(dst, src); err != nil {
-			return err
+		if deduped {
+			if cleanup != nil {
+				cleanup()
+			}
+			return c.JSON(http.StatusAccepted, map[string]string{"task_id": taskID, "deduped": "true"})
 		}
 
-		s.AddTask(&store.Task{ID: id, Status: "Pending", Progress: 0, SourcePath: dstPath})
+		return c.JSON(http.StatusAccepted, map[string]string{"task_id": taskID})
+	}
+}
 
-		select {
-		case tasks <- id:
-		default:
-			// queue full: mark queued and enqueue asynchronously
-			_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Queued" })
-			go func() { tasks <- id }()
-		}
+// enqueueUpload is the single insertion point both the multipart upload
+// path (UploadHandler) and the resumable upload path
+// (FinalizeUploadSessionHandler) funnel through, so neither can drift
+// out of sync on handoff tokens or dedup semantics. id is the task ID to
+// use if this upload turns out not to be a duplicate.
+//
+// Dedup happens in two layers: q.Enqueue catches a second upload of the
+// same bytes while the first is still queued or running, and
+// Store.FindCompletedByHash catches one that arrives after the first
+// has already finished and dropped out of the queue's idempotency
+// index.
+func enqueueUpload(s *store.Store, q queue.Queue, handoff *secret.HandoffSigner, principal *auth.Principal, id, sourcePath, sha string) (taskID string, deduped bool, err error) {
+	if existing, ok := s.FindCompletedByHash(principal.TenantID, sha); ok {
+		return existing.ID, true, nil
+	}
+
+	// Scope the idempotency key by tenant so two tenants uploading the
+	// same bytes never collapse onto each other's task, which would
+	// otherwise hand one tenant a task_id it doesn't own.
+	idempotencyKey := principal.TenantID + ":" + sha
 
-		return c.JSON(http.StatusAccepted, map[string]string{"task_id": id})
+	existingID, queueDeduped := q.Enqueue(id, queue.PriorityNormal, idempotencyKey)
+	if queueDeduped {
+		return existingID, true, nil
 	}
+
+	// Hand the task to the worker pool with a short-lived token proving
+	// it's the gateway that enqueued this exact file/hash, so a worker
+	// (in-process or an out-of-process callback caller) never acts on a
+	// task_id it merely guessed.
+	token, err := handoff.Issue(id, sourcePath, sha, secret.DefaultTTL)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to issue handoff token: %w", err)
+	}
+
+	if err := s.AddTask(&store.Task{ID: id, OwnerID: principal.TenantID, Status: "Queued", Progress: 0, SourcePath: sourcePath, SHA256: sha, HandoffToken: token}); err != nil {
+		return "", false, err
+	}
+	return id, false, nil
 }
 
-// StatusHandler returns task status.
-func StatusHandler(s *store.Store) echo.HandlerFunc {
+// statusResponse wraps a Task with the one piece of backend-derived
+// information clients find useful: the size of the uploaded source, if
+// it's still present. It embeds rather than extending store.Task itself
+// so the store's JSON contract stays backend-agnostic.
+type statusResponse struct {
+	*store.Task
+	SourceSize *int64 `json:"source_size,omitempty"`
+}
+
+// StatusHandler returns task status. It takes a Backend so it can
+// report the uploaded source's size without assuming SourcePath is a
+// local path it can os.Stat directly; a Stat error (e.g. the object was
+// already cleaned up) is not fatal, it just omits source_size.
+func StatusHandler(s *store.Store, backend storage.Backend) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
 		id := c.Param("id")
-		t, ok := s.GetTask(id)
+		t, ok := s.GetTaskForOwner(id, principal.TenantID)
 		if !ok {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "task not found"})
 		}
-		return c.JSON(http.StatusOK, t)
+
+		resp := statusResponse{Task: t}
+		if t.SourcePath != "" {
+			if stat, err := backend.Stat(c.Request().Context(), t.SourcePath); err == nil {
+				resp.SourceSize = &stat.Size
+			}
+		}
+		return c.JSON(http.StatusOK, resp)
 	}
 }
 
-// ReportHandler returns the JSON report if available.
+// ReportHandler returns the structured report, rendered in the format
+// requested via ?format=json|sarif|html (falling back to the Accept
+// header, then to JSON for backward compatibility).
 func ReportHandler(s *store.Store) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
 		id := c.Param("id")
-		t, ok := s.GetTask(id)
+		t, ok := s.GetTaskForOwner(id, principal.TenantID)
 		if !ok {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "task not found"})
 		}
@@ -86,31 +188,170 @@ func ReportHandler(s *store.Store) echo.HandlerFunc {
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "cannot open report"})
 		}
 		defer f.Close()
-		var buf map[string]any
-		if err := json.NewDecoder(f).Decode(&buf); err != nil {
+		var rep report.Report
+		if err := json.NewDecoder(f).Decode(&rep); err != nil {
 			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "invalid report"})
 		}
-		return c.JSON(http.StatusOK, buf)
+
+		format := c.QueryParam("format")
+		if format == "" {
+			format = formatFromAccept(c.Request().Header.Get(echo.HeaderAccept))
+		}
+		renderer, ok := report.RendererFor(format)
+		if !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "unsupported format: " + format})
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, renderer.ContentType())
+		c.Response().WriteHeader(http.StatusOK)
+		return renderer.Render(c.Response(), &rep)
+	}
+}
+
+// formatFromAccept maps a client's Accept header to a renderer format
+// name; unrecognized or absent headers default to "json".
+func formatFromAccept(accept string) string {
+	switch {
+	case strings.Contains(accept, "sarif"):
+		return "sarif"
+	case strings.Contains(accept, "text/html"):
+		return "html"
+	default:
+		return "json"
 	}
 }
 
-// DownloadHandler serves the annotated docx or report.
-func DownloadHandler(s *store.Store) echo.HandlerFunc {
+// DownloadHandler no longer serves file bytes itself: it checks the
+// caller owns the task, then mints a short-lived signed URL for
+// GET /api/v1/download/signed so the actual transfer (e.g. from a CDN)
+// never needs to re-authenticate against the gateway or the task store.
+func DownloadHandler(s *store.Store, signer *auth.URLSigner) echo.HandlerFunc {
 	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
 		id := c.Param("id")
-		t, ok := s.GetTask(id)
+		t, ok := s.GetTaskForOwner(id, principal.TenantID)
 		if !ok {
 			return c.JSON(http.StatusNotFound, map[string]string{"error": "task not found"})
 		}
 		if t.Status != "Completed" {
 			return c.NoContent(http.StatusAccepted)
 		}
-		if t.AnnotatedPath != "" {
-			return c.File(t.AnnotatedPath)
+
+		path := t.AnnotatedPath
+		if path == "" {
+			path = t.ReportPath
+		}
+		if path == "" {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "nothing to download"})
+		}
+
+		token := signer.Sign(t.ID, path, signedDownloadTTL)
+		return c.JSON(http.StatusOK, map[string]string{"url": "/api/v1/download/signed?token=" + token})
+	}
+}
+
+// SignedDownloadHandler serves the file named in a token minted by
+// DownloadHandler. It deliberately sits outside auth.Middleware: the
+// token itself is the credential, scoped to one file and expiring in
+// signedDownloadTTL, which is what lets it be handed to a CDN.
+func SignedDownloadHandler(signer *auth.URLSigner) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.QueryParam("token")
+		_, path, ok := signer.Verify(token)
+		if !ok {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "invalid or expired token"})
 		}
-		if t.ReportPath != "" {
-			return c.File(t.ReportPath)
+		return c.File(path)
+	}
+}
+
+// WorkerStatusUpdate is the body an out-of-process worker POSTs back to
+// WorkerCallbackHandler to report progress on a task it was handed off.
+type WorkerStatusUpdate struct {
+	Status    string `json:"status"`
+	Progress  int    `json:"progress"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// WorkerCallbackHandler lets a worker that isn't running in-process
+// with the gateway report status back over HTTP. It trusts the caller
+// only as far as the bearer token proves: the token must be signed with
+// the same handoff secret UploadHandler used, and its task_id claim
+// must match :id, so a worker can't overwrite a task it was never
+// handed via the queue.
+func WorkerCallbackHandler(s *store.Store, handoff *secret.HandoffSigner) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id := c.Param("id")
+
+		header := c.Request().Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "missing bearer token"})
+		}
+		claims, err := handoff.Verify(strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
 		}
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "nothing to download"})
+		if claims.TaskID != id {
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "token does not authorize this task"})
+		}
+
+		var body WorkerStatusUpdate
+		if err := json.NewDecoder(c.Request().Body).Decode(&body); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid body"})
+		}
+
+		ok := s.UpdateTask(id, func(t *store.Task) {
+			t.Status = body.Status
+			t.Progress = body.Progress
+			if body.LastError != "" {
+				t.LastError = body.LastError
+			}
+		})
+		if !ok {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "task not found"})
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// cancellableStatuses are the states a task can be interrupted from; a
+// task that already reached a terminal state has nothing left to cancel.
+var cancellableStatuses = map[string]bool{
+	"Queued": true, "Parsing": true, "Auditing": true,
+}
+
+// DeleteHandler marks a task cancelled and, if a worker is currently
+// processing it, interrupts it via Store.CancelTask. The task moves to
+// "Cancelling" immediately so a worker that hasn't registered a cancel
+// func yet (e.g. it's still queued) knows to skip it once dequeued.
+func DeleteHandler(s *store.Store) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
+		id := c.Param("id")
+		t, ok := s.GetTaskForOwner(id, principal.TenantID)
+		if !ok {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "task not found"})
+		}
+		if !cancellableStatuses[t.Status] {
+			return c.JSON(http.StatusConflict, map[string]string{"error": "task already in a terminal state: " + t.Status})
+		}
+
+		s.UpdateTask(id, func(t *store.Task) { t.Status = "Cancelling" })
+		s.CancelTask(id)
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
+// QueueStatsHandler reports queue depth, in-flight count, oldest-age,
+// and dead-letter size so operators can watch for backlog building up.
+func QueueStatsHandler(q queue.Queue) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, q.Stats())
 	}
 }
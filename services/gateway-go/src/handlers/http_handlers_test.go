@@ -2,36 +2,41 @@ package handlers
 
 import (
     "bytes"
+    "context"
     "encoding/json"
     "mime/multipart"
     "net/http"
     "net/http/httptest"
-    "os"
-    "path/filepath"
     "testing"
     "time"
 
+    "github.com/golang-jwt/jwt/v5"
     "github.com/labstack/echo/v4"
 
+    "github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/auth"
+    "github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/queue"
+    "github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/secret"
+    "github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
     "github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
 )
 
-func TestUploadAndStatusHandlers(t *testing.T) {
-    // isolate filesystem side effects
-    tmpRoot := t.TempDir()
-    workDir := filepath.Join(tmpRoot, "wd")
-    if err := os.MkdirAll(workDir, 0o755); err != nil {
-        t.Fatalf("mkdir workdir: %v", err)
-    }
-    oldWD, _ := os.Getwd()
-    if err := os.Chdir(workDir); err != nil {
-        t.Fatalf("chdir: %v", err)
-    }
-    t.Cleanup(func() { _ = os.Chdir(oldWD) })
+var testPrincipal = &auth.Principal{TenantID: "tenant-a", UserID: "user-a"}
+
+var testHandoffSigner = secret.NewHandoffSigner([]byte("test-handoff-secret"))
+
+func withTestPrincipal(c echo.Context) echo.Context {
+    c.Set("principal", testPrincipal)
+    return c
+}
 
+func TestUploadAndStatusHandlers(t *testing.T) {
     e := echo.New()
     s := store.NewStore()
-    tasks := make(chan string, 1)
+    q := queue.NewMemoryQueue()
+    backend, err := storage.NewLocalFS(t.TempDir())
+    if err != nil {
+        t.Fatalf("new local fs: %v", err)
+    }
 
     // build multipart form with a dummy file
     var body bytes.Buffer
@@ -49,7 +54,7 @@ func TestUploadAndStatusHandlers(t *testing.T) {
     req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
     rec := httptest.NewRecorder()
 
-    if err := UploadHandler(s, tasks)(e.NewContext(req, rec)); err != nil {
+    if err := UploadHandler(s, q, testHandoffSigner, backend)(withTestPrincipal(e.NewContext(req, rec))); err != nil {
         t.Fatalf("upload handler error: %v", err)
     }
     if rec.Code != http.StatusAccepted {
@@ -71,14 +76,15 @@ func TestUploadAndStatusHandlers(t *testing.T) {
     }
 
     // task should be enqueued
-    select {
-    case got := <-tasks:
-        if got != id {
-            t.Fatalf("queued id mismatch: %s", got)
-        }
-    case <-time.After(2 * time.Second):
+    dctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+    defer cancel()
+    item, ok := q.Dequeue(dctx)
+    if !ok {
         t.Fatalf("task not enqueued")
     }
+    if item.TaskID != id {
+        t.Fatalf("queued id mismatch: %s", item.TaskID)
+    }
 
     // status handler should return 200
     req2 := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/"+id, nil)
@@ -86,10 +92,182 @@ func TestUploadAndStatusHandlers(t *testing.T) {
     ctx2 := e.NewContext(req2, rec2)
     ctx2.SetParamNames("id")
     ctx2.SetParamValues(id)
-    if err := StatusHandler(s)(ctx2); err != nil {
+    if err := StatusHandler(s, backend)(withTestPrincipal(ctx2)); err != nil {
         t.Fatalf("status handler error: %v", err)
     }
     if rec2.Code != http.StatusOK {
         t.Fatalf("expected 200, got %d", rec2.Code)
     }
 }
+
+func TestUploadHandlerDedupesByContentHash(t *testing.T) {
+    e := echo.New()
+    s := store.NewStore()
+    q := queue.NewMemoryQueue()
+    backend, err := storage.NewLocalFS(t.TempDir())
+    if err != nil {
+        t.Fatalf("new local fs: %v", err)
+    }
+
+    upload := func() map[string]string {
+        var body bytes.Buffer
+        writer := multipart.NewWriter(&body)
+        fw, err := writer.CreateFormFile("file", "sample.docx")
+        if err != nil {
+            t.Fatalf("create form file: %v", err)
+        }
+        if _, err := fw.Write([]byte("identical bytes")); err != nil {
+            t.Fatalf("write form file: %v", err)
+        }
+        writer.Close()
+
+        req := httptest.NewRequest(http.MethodPost, "/api/v1/upload", &body)
+        req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+        rec := httptest.NewRecorder()
+        if err := UploadHandler(s, q, testHandoffSigner, backend)(withTestPrincipal(e.NewContext(req, rec))); err != nil {
+            t.Fatalf("upload handler error: %v", err)
+        }
+        var resp map[string]string
+        if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+            t.Fatalf("parse response: %v", err)
+        }
+        return resp
+    }
+
+    first := upload()
+    second := upload()
+
+    if second["deduped"] != "true" {
+        t.Fatalf("expected second upload to be deduped, got %+v", second)
+    }
+    if second["task_id"] != first["task_id"] {
+        t.Fatalf("expected deduped upload to reuse task_id %s, got %s", first["task_id"], second["task_id"])
+    }
+}
+
+func TestDeleteHandlerCancelsQueuedTask(t *testing.T) {
+    e := echo.New()
+    s := store.NewStore()
+    id := "task-1"
+    if err := s.AddTask(&store.Task{ID: id, OwnerID: testPrincipal.TenantID, Status: "Queued"}); err != nil {
+        t.Fatalf("add task: %v", err)
+    }
+
+    req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/"+id, nil)
+    rec := httptest.NewRecorder()
+    ctx := e.NewContext(req, rec)
+    ctx.SetParamNames("id")
+    ctx.SetParamValues(id)
+    if err := DeleteHandler(s)(withTestPrincipal(ctx)); err != nil {
+        t.Fatalf("delete handler error: %v", err)
+    }
+    if rec.Code != http.StatusAccepted {
+        t.Fatalf("expected 202, got %d", rec.Code)
+    }
+
+    got, ok := s.GetTask(id)
+    if !ok {
+        t.Fatalf("task disappeared")
+    }
+    if got.Status != "Cancelling" {
+        t.Fatalf("expected status Cancelling, got %s", got.Status)
+    }
+}
+
+func TestDeleteHandlerRejectsTerminalTask(t *testing.T) {
+    e := echo.New()
+    s := store.NewStore()
+    id := "task-2"
+    if err := s.AddTask(&store.Task{ID: id, OwnerID: testPrincipal.TenantID, Status: "Completed"}); err != nil {
+        t.Fatalf("add task: %v", err)
+    }
+
+    req := httptest.NewRequest(http.MethodDelete, "/api/v1/tasks/"+id, nil)
+    rec := httptest.NewRecorder()
+    ctx := e.NewContext(req, rec)
+    ctx.SetParamNames("id")
+    ctx.SetParamValues(id)
+    if err := DeleteHandler(s)(withTestPrincipal(ctx)); err != nil {
+        t.Fatalf("delete handler error: %v", err)
+    }
+    if rec.Code != http.StatusConflict {
+        t.Fatalf("expected 409, got %d", rec.Code)
+    }
+}
+
+func TestWorkerCallbackHandlerAcceptsValidToken(t *testing.T) {
+    e := echo.New()
+    s := store.NewStore()
+    id := "task-3"
+    if err := s.AddTask(&store.Task{ID: id, OwnerID: testPrincipal.TenantID, Status: "Parsing", SourcePath: "/tmp/sample.docx"}); err != nil {
+        t.Fatalf("add task: %v", err)
+    }
+
+    token, err := testHandoffSigner.Issue(id, "/tmp/sample.docx", "deadbeef", secret.DefaultTTL)
+    if err != nil {
+        t.Fatalf("issue token: %v", err)
+    }
+
+    body, _ := json.Marshal(WorkerStatusUpdate{Status: "Auditing", Progress: 55})
+    req := httptest.NewRequest(http.MethodPost, "/internal/tasks/"+id+"/status", bytes.NewReader(body))
+    req.Header.Set("Authorization", "Bearer "+token)
+    rec := httptest.NewRecorder()
+    ctx := e.NewContext(req, rec)
+    ctx.SetParamNames("id")
+    ctx.SetParamValues(id)
+
+    if err := WorkerCallbackHandler(s, testHandoffSigner)(ctx); err != nil {
+        t.Fatalf("worker callback handler error: %v", err)
+    }
+    if rec.Code != http.StatusNoContent {
+        t.Fatalf("expected 204, got %d", rec.Code)
+    }
+
+    got, ok := s.GetTask(id)
+    if !ok {
+        t.Fatalf("task disappeared")
+    }
+    if got.Status != "Auditing" || got.Progress != 55 {
+        t.Fatalf("status update not applied: %+v", got)
+    }
+}
+
+func TestWorkerCallbackHandlerRejectsUnexpectedSigningMethod(t *testing.T) {
+    e := echo.New()
+    s := store.NewStore()
+    id := "task-4"
+    if err := s.AddTask(&store.Task{ID: id, OwnerID: testPrincipal.TenantID, Status: "Parsing", SourcePath: "/tmp/sample.docx"}); err != nil {
+        t.Fatalf("add task: %v", err)
+    }
+
+    claims := secret.HandoffClaims{
+        TaskID:   id,
+        FilePath: "/tmp/sample.docx",
+        SHA256:   "deadbeef",
+    }
+    unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+    token, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+    if err != nil {
+        t.Fatalf("sign none token: %v", err)
+    }
+
+    body, _ := json.Marshal(WorkerStatusUpdate{Status: "Auditing", Progress: 55})
+    req := httptest.NewRequest(http.MethodPost, "/internal/tasks/"+id+"/status", bytes.NewReader(body))
+    req.Header.Set("Authorization", "Bearer "+token)
+    rec := httptest.NewRecorder()
+    ctx := e.NewContext(req, rec)
+    ctx.SetParamNames("id")
+    ctx.SetParamValues(id)
+
+    if err := WorkerCallbackHandler(s, testHandoffSigner)(ctx); err != nil {
+        t.Fatalf("worker callback handler error: %v", err)
+    }
+    if rec.Code != http.StatusUnauthorized {
+        t.Fatalf("expected 401, got %d", rec.Code)
+    }
+
+    got, _ := s.GetTask(id)
+    if got.Status != "Parsing" {
+        t.Fatalf("task should not have been updated, got status %s", got.Status)
+    }
+}
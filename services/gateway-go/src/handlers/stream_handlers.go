@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/auth"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+)
+
+const ssePingInterval = 15 * time.Second
+
+// EventsHandler streams status/progress transitions for a single task as
+// Server-Sent Events. The Last-Event-ID header of a reconnecting client is
+// not read and no past updates are replayed: every connection, new or
+// resumed, starts from the task's current snapshot.
+func EventsHandler(s *store.Store) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
+		id := c.Param("id")
+		t, ok := s.GetTaskForOwner(id, principal.TenantID)
+		if !ok {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "task not found"})
+		}
+
+		w := c.Response()
+		w.Header().Set(echo.HeaderContentType, "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		updates, unsubscribe := s.Subscribe(id)
+		defer unsubscribe()
+
+		// Emit the current snapshot immediately so a client that just
+		// subscribed doesn't wait for the next transition.
+		writeSSEEvent(w, store.Update{ID: t.ID, Status: t.Status, Progress: t.Progress, Stage: t.Status})
+		w.Flush()
+
+		ticker := time.NewTicker(ssePingInterval)
+		defer ticker.Stop()
+
+		ctx := c.Request().Context()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case u := <-updates:
+				writeSSEEvent(w, u)
+				w.Flush()
+				if u.Status == "Completed" || isTerminalError(u.Status) {
+					return nil
+				}
+			case <-ticker.C:
+				fmt.Fprint(w, ": ping\n\n")
+				w.Flush()
+			}
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, u store.Update) {
+	fmt.Fprintf(w, "id: %s\n", strconv.FormatUint(u.Seq, 10))
+	fmt.Fprintf(w, "event: task.update\n")
+	fmt.Fprintf(w, "data: {\"id\":%q,\"status\":%q,\"progress\":%d,\"stage\":%q}\n\n",
+		u.ID, u.Status, u.Progress, u.Stage)
+}
+
+func isTerminalError(status string) bool {
+	return len(status) >= 5 && status[:5] == "Error"
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Echo already fronts this with its own CORS/middleware stack; the
+	// upgrader itself stays permissive and relies on that layer.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// jsonRPCUpdate is the JSON-RPC 2.0 notification frame sent over the
+// websocket for every task update, per the "task.update" method the
+// gateway's clients already expect from the SSE payload shape.
+type jsonRPCUpdate struct {
+	JSONRPC string            `json:"jsonrpc"`
+	Method  string            `json:"method"`
+	Params  jsonRPCTaskParams `json:"params"`
+}
+
+type jsonRPCTaskParams struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	Stage    string `json:"stage"`
+}
+
+// wsSubscribeRequest is what a client sends to add a task ID to its
+// multiplexed subscription set: {"jsonrpc":"2.0","method":"task.subscribe","params":{"id":"..."}}
+type wsSubscribeRequest struct {
+	Method string `json:"method"`
+	Params struct {
+		ID string `json:"id"`
+	} `json:"params"`
+}
+
+// WSHandler multiplexes task.update notifications for many tasks over a
+// single WebSocket connection. Clients opt into tasks by sending a
+// task.subscribe frame; each subscription fans into the same outbound
+// writer goroutine so one slow client can't be written to concurrently
+// from multiple goroutines (gorilla/websocket connections aren't safe
+// for concurrent writers).
+func WSHandler(s *store.Store) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
+
+		conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		out := make(chan jsonRPCUpdate, 64)
+		done := make(chan struct{})
+		// unsubs is written by the reader goroutine below but read by
+		// this function's deferred cleanup, which can run the instant
+		// conn.WriteJSON fails in the select loop further down — i.e.
+		// concurrently with the reader goroutine still adding entries.
+		// unsubsMu makes both sides of that safe.
+		var unsubsMu sync.Mutex
+		unsubs := make(map[string]func())
+		defer func() {
+			unsubsMu.Lock()
+			defer unsubsMu.Unlock()
+			for _, unsub := range unsubs {
+				unsub()
+			}
+		}()
+
+		go func() {
+			defer close(done)
+			for {
+				var req wsSubscribeRequest
+				if err := conn.ReadJSON(&req); err != nil {
+					return
+				}
+				if req.Method != "task.subscribe" || req.Params.ID == "" {
+					continue
+				}
+				unsubsMu.Lock()
+				_, already := unsubs[req.Params.ID]
+				unsubsMu.Unlock()
+				if already {
+					continue
+				}
+				if _, ok := s.GetTaskForOwner(req.Params.ID, principal.TenantID); !ok {
+					continue
+				}
+				updates, unsub := s.Subscribe(req.Params.ID)
+				unsubsMu.Lock()
+				unsubs[req.Params.ID] = unsub
+				unsubsMu.Unlock()
+				go forwardUpdates(req.Params.ID, updates, out, done)
+			}
+		}()
+
+		for {
+			select {
+			case <-done:
+				return nil
+			case u := <-out:
+				if err := conn.WriteJSON(u); err != nil {
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func forwardUpdates(taskID string, updates <-chan store.Update, out chan<- jsonRPCUpdate, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case u, ok := <-updates:
+			if !ok {
+				return
+			}
+			msg := jsonRPCUpdate{
+				JSONRPC: "2.0",
+				Method:  "task.update",
+				Params:  jsonRPCTaskParams{ID: u.ID, Status: u.Status, Progress: u.Progress, Stage: u.Stage},
+			}
+			select {
+			case out <- msg:
+			case <-done:
+				return
+			default:
+				// backpressure: drop rather than block the fan-in loop
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/auth"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+)
+
+// wsTestServer wraps WSHandler in a real echo server, since the upgrade
+// handshake needs a real net.Conn, not an httptest.ResponseRecorder.
+func wsTestServer(t *testing.T, s *store.Store) (wsURL string) {
+	t.Helper()
+	e := echo.New()
+	e.GET("/ws", func(c echo.Context) error {
+		c.Set("principal", &auth.Principal{TenantID: "tenant-a", UserID: "user-a"})
+		return WSHandler(s)(c)
+	})
+	srv := httptest.NewServer(e)
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+}
+
+func TestEventsHandlerStreamsSnapshotAndUpdate(t *testing.T) {
+	e := echo.New()
+	s := store.NewStore()
+	s.AddTask(&store.Task{ID: "t1", OwnerID: "tenant-a", Status: "Pending", Progress: 0})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tasks/t1/events", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("t1")
+	ctx.Set("principal", &auth.Principal{TenantID: "tenant-a", UserID: "user-a"})
+
+	done := make(chan error, 1)
+	go func() { done <- EventsHandler(s)(ctx) }()
+
+	// give the handler a moment to subscribe and emit the initial snapshot
+	time.Sleep(50 * time.Millisecond)
+	s.UpdateTask("t1", func(t *store.Task) { t.Status = "Completed"; t.Progress = 100 })
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("events handler error: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("events handler did not return after terminal update")
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, `"status":"Pending"`) {
+		t.Fatalf("expected initial snapshot in stream, got: %s", body)
+	}
+	if !strings.Contains(body, `"status":"Completed"`) {
+		t.Fatalf("expected terminal update in stream, got: %s", body)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	var eventLines int
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "event: task.update") {
+			eventLines++
+		}
+	}
+	if eventLines != 2 {
+		t.Fatalf("expected 2 task.update events, got %d", eventLines)
+	}
+}
+
+func TestWSHandlerSubscribeAndReceiveUpdate(t *testing.T) {
+	s := store.NewStore()
+	s.AddTask(&store.Task{ID: "t1", OwnerID: "tenant-a", Status: "Pending", Progress: 0})
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsTestServer(t, s), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	sub := map[string]any{"jsonrpc": "2.0", "method": "task.subscribe", "params": map[string]string{"id": "t1"}}
+	if err := conn.WriteJSON(sub); err != nil {
+		t.Fatalf("write subscribe: %v", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	s.UpdateTask("t1", func(t *store.Task) { t.Status = "Auditing"; t.Progress = 40 })
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg jsonRPCUpdate
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read update: %v", err)
+	}
+	if msg.Params.ID != "t1" || msg.Params.Status != "Auditing" {
+		t.Fatalf("unexpected update: %+v", msg)
+	}
+}
+
+// TestWSHandlerConcurrentSubscribeAndDisconnect exercises the unsubs map
+// race this test was written to catch: many task.subscribe frames keep
+// the reader goroutine writing into unsubs while the client disconnects,
+// triggering WSHandler's deferred cleanup (which ranges over unsubs) on
+// the outer goroutine at the same time. Run with `go test -race`.
+func TestWSHandlerConcurrentSubscribeAndDisconnect(t *testing.T) {
+	s := store.NewStore()
+	const n = 20
+	for i := 0; i < n; i++ {
+		s.AddTask(&store.Task{ID: fmt.Sprintf("t%d", i), OwnerID: "tenant-a", Status: "Pending"})
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsTestServer(t, s), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+
+	for i := 0; i < n; i++ {
+		sub := map[string]any{"jsonrpc": "2.0", "method": "task.subscribe", "params": map[string]string{"id": fmt.Sprintf("t%d", i)}}
+		if err := conn.WriteJSON(sub); err != nil {
+			t.Fatalf("write subscribe %d: %v", i, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < n; i++ {
+			s.UpdateTask(fmt.Sprintf("t%d", i), func(t *store.Task) { t.Progress++ })
+		}
+	}()
+
+	// Close from the client side as soon as subscriptions are in flight,
+	// so the server's outer goroutine races the reader goroutine's
+	// unsubs writes instead of running safely after it's done.
+	conn.Close()
+	wg.Wait()
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/auth"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/queue"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/resumable"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/secret"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+)
+
+// CreateUploadSessionHandler starts a resumable upload session for
+// inputs too large, or connections too flaky, to trust to a single
+// multipart POST, and returns the session ID plus the chunk size the
+// client should PATCH in.
+func CreateUploadSessionHandler(sessions *resumable.Manager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
+		sess, err := sessions.Create(principal.TenantID)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to create upload session"})
+		}
+		return c.JSON(http.StatusCreated, map[string]any{
+			"session_id": sess.ID,
+			"chunk_size": resumable.DefaultChunkSize,
+		})
+	}
+}
+
+// UploadChunkHandler appends one Content-Range chunk to an upload
+// session. Chunks must extend the session contiguously from its current
+// offset; a replayed or out-of-order chunk is rejected with 409 rather
+// than silently corrupting the assembled file.
+func UploadChunkHandler(sessions *resumable.Manager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
+		sess, ok := sessions.Get(c.Param("sid"))
+		if !ok || sess.OwnerID != principal.TenantID {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "upload session not found"})
+		}
+
+		start, end, total, err := parseContentRange(c.Request().Header.Get("Content-Range"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if err := sess.WriteChunk(start, end, total, c.Request().Body); err != nil {
+			return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+		}
+
+		c.Response().Header().Set("Upload-Offset", strconv.FormatInt(sess.Offset(), 10))
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// UploadSessionStatusHandler reports how many contiguous bytes an
+// upload session has received, so a client resuming after a dropped
+// connection knows where to seek before PATCHing its next chunk.
+func UploadSessionStatusHandler(sessions *resumable.Manager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.NoContent(http.StatusUnauthorized)
+		}
+		sess, ok := sessions.Get(c.Param("sid"))
+		if !ok || sess.OwnerID != principal.TenantID {
+			return c.NoContent(http.StatusNotFound)
+		}
+		c.Response().Header().Set("Upload-Offset", strconv.FormatInt(sess.Offset(), 10))
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// FinalizeUploadSessionHandler verifies the assembled file's hash
+// against what the client claims, puts it through backend, and funnels
+// it through the same enqueueUpload step UploadHandler uses for a
+// single-request upload, so a resumable upload dedupes identically to a
+// multipart one.
+func FinalizeUploadSessionHandler(s *store.Store, q queue.Queue, handoff *secret.HandoffSigner, backend storage.Backend, sessions *resumable.Manager) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		principal, ok := auth.FromContext(c)
+		if !ok {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unauthenticated"})
+		}
+		sess, ok := sessions.Get(c.Param("sid"))
+		if !ok || sess.OwnerID != principal.TenantID {
+			return c.JSON(http.StatusNotFound, map[string]string{"error": "upload session not found"})
+		}
+
+		var reqBody struct {
+			SHA256 string `json:"sha256"`
+		}
+		if err := json.NewDecoder(c.Request().Body).Decode(&reqBody); err != nil || reqBody.SHA256 == "" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "sha256 required"})
+		}
+
+		actual, err := hashFile(sess.TempPath)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to hash upload"})
+		}
+		if actual != reqBody.SHA256 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "sha256 mismatch"})
+		}
+
+		assembled, err := os.Open(sess.TempPath)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to finalize upload"})
+		}
+		dstPath, _, err := backend.Put(c.Request().Context(), sess.ID+".docx", assembled)
+		assembled.Close()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to finalize upload"})
+		}
+		_ = os.Remove(sess.TempPath)
+		sessions.Delete(sess.ID)
+
+		taskID, deduped, err := enqueueUpload(s, q, handoff, principal, sess.ID, dstPath, actual)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		if deduped {
+			_ = backend.Delete(c.Request().Context(), dstPath)
+			return c.JSON(http.StatusAccepted, map[string]string{"task_id": taskID, "deduped": "true"})
+		}
+		return c.JSON(http.StatusAccepted, map[string]string{"task_id": taskID})
+	}
+}
+
+// parseContentRange parses a "bytes N-M/Total" Content-Range header, the
+// same form tus.io and GitLab's resumable upload protocol use.
+func parseContentRange(header string) (start, end, total int64, err error) {
+	if !strings.HasPrefix(header, "bytes ") {
+		return 0, 0, 0, fmt.Errorf("upload: missing or malformed Content-Range")
+	}
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		return 0, 0, 0, fmt.Errorf("upload: malformed Content-Range: %w", err)
+	}
+	return start, end, total, nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
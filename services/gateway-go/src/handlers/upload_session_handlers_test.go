@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/queue"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/resumable"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+)
+
+func contentRange(start, end, total int) string {
+	return fmt.Sprintf("bytes %d-%d/%d", start, end, total)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return data
+}
+
+func testCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestResumableUploadInTwoChunksThenFinalize(t *testing.T) {
+	e := echo.New()
+	s := store.NewStore()
+	q := queue.NewMemoryQueue()
+	sessions, err := resumable.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	backend, err := storage.NewLocalFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local fs: %v", err)
+	}
+
+	content := []byte("the quick brown fox jumps over the lazy dog")
+	chunk1, chunk2 := content[:20], content[20:]
+
+	createReq := httptest.NewRequest(http.MethodPost, "/api/v1/upload/session", nil)
+	createRec := httptest.NewRecorder()
+	if err := CreateUploadSessionHandler(sessions)(withTestPrincipal(e.NewContext(createReq, createRec))); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	var created map[string]any
+	if err := json.Unmarshal(createRec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("parse create response: %v", err)
+	}
+	sid, _ := created["session_id"].(string)
+	if sid == "" {
+		t.Fatalf("session_id empty")
+	}
+
+	patch := func(body []byte, start, end, total int) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/upload/session/"+sid, bytes.NewReader(body))
+		req.Header.Set("Content-Range", contentRange(start, end, total))
+		rec := httptest.NewRecorder()
+		ctx := e.NewContext(req, rec)
+		ctx.SetParamNames("sid")
+		ctx.SetParamValues(sid)
+		if err := UploadChunkHandler(sessions)(withTestPrincipal(ctx)); err != nil {
+			t.Fatalf("upload chunk: %v", err)
+		}
+		return rec
+	}
+
+	rec1 := patch(chunk1, 0, len(chunk1)-1, len(content))
+	if rec1.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 for first chunk, got %d", rec1.Code)
+	}
+	if got := rec1.Header().Get("Upload-Offset"); got != fmt.Sprintf("%d", len(chunk1)) {
+		t.Fatalf("expected Upload-Offset %d, got %s", len(chunk1), got)
+	}
+
+	// Replaying the first chunk must be rejected: the session's offset
+	// has already moved past it.
+	replay := patch(chunk1, 0, len(chunk1)-1, len(content))
+	if replay.Code != http.StatusConflict {
+		t.Fatalf("expected 409 replaying a chunk, got %d", replay.Code)
+	}
+
+	rec2 := patch(chunk2, len(chunk1), len(content)-1, len(content))
+	if rec2.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 for second chunk, got %d", rec2.Code)
+	}
+	if got := rec2.Header().Get("Upload-Offset"); got != fmt.Sprintf("%d", len(content)) {
+		t.Fatalf("expected Upload-Offset %d, got %s", len(content), got)
+	}
+
+	sum := sha256Hex(content)
+	finalizeReq := httptest.NewRequest(http.MethodPost, "/api/v1/upload/session/"+sid+"/finalize", bytes.NewReader(mustJSON(t, map[string]string{"sha256": sum})))
+	finalizeRec := httptest.NewRecorder()
+	finalizeCtx := e.NewContext(finalizeReq, finalizeRec)
+	finalizeCtx.SetParamNames("sid")
+	finalizeCtx.SetParamValues(sid)
+	if err := FinalizeUploadSessionHandler(s, q, testHandoffSigner, backend, sessions)(withTestPrincipal(finalizeCtx)); err != nil {
+		t.Fatalf("finalize: %v", err)
+	}
+	if finalizeRec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", finalizeRec.Code)
+	}
+
+	var finalized map[string]string
+	if err := json.Unmarshal(finalizeRec.Body.Bytes(), &finalized); err != nil {
+		t.Fatalf("parse finalize response: %v", err)
+	}
+	taskID := finalized["task_id"]
+	if taskID == "" {
+		t.Fatalf("task_id empty")
+	}
+	if _, ok := s.GetTask(taskID); !ok {
+		t.Fatalf("task not stored")
+	}
+	if _, ok := sessions.Get(sid); ok {
+		t.Fatalf("session should have been forgotten after finalize")
+	}
+}
+
+func TestResumableUploadFinalizeDedupesCompletedHash(t *testing.T) {
+	e := echo.New()
+	s := store.NewStore()
+	q := queue.NewMemoryQueue()
+	sessions, err := resumable.NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	backend, err := storage.NewLocalFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local fs: %v", err)
+	}
+
+	content := []byte("identical content for dedup test")
+	sum := sha256Hex(content)
+
+	upload := func() map[string]string {
+		createReq := httptest.NewRequest(http.MethodPost, "/api/v1/upload/session", nil)
+		createRec := httptest.NewRecorder()
+		if err := CreateUploadSessionHandler(sessions)(withTestPrincipal(e.NewContext(createReq, createRec))); err != nil {
+			t.Fatalf("create session: %v", err)
+		}
+		var created map[string]any
+		if err := json.Unmarshal(createRec.Body.Bytes(), &created); err != nil {
+			t.Fatalf("parse create response: %v", err)
+		}
+		sid := created["session_id"].(string)
+
+		patchReq := httptest.NewRequest(http.MethodPatch, "/api/v1/upload/session/"+sid, bytes.NewReader(content))
+		patchReq.Header.Set("Content-Range", contentRange(0, len(content)-1, len(content)))
+		patchRec := httptest.NewRecorder()
+		patchCtx := e.NewContext(patchReq, patchRec)
+		patchCtx.SetParamNames("sid")
+		patchCtx.SetParamValues(sid)
+		if err := UploadChunkHandler(sessions)(withTestPrincipal(patchCtx)); err != nil {
+			t.Fatalf("upload chunk: %v", err)
+		}
+
+		finalizeReq := httptest.NewRequest(http.MethodPost, "/api/v1/upload/session/"+sid+"/finalize", bytes.NewReader(mustJSON(t, map[string]string{"sha256": sum})))
+		finalizeRec := httptest.NewRecorder()
+		finalizeCtx := e.NewContext(finalizeReq, finalizeRec)
+		finalizeCtx.SetParamNames("sid")
+		finalizeCtx.SetParamValues(sid)
+		if err := FinalizeUploadSessionHandler(s, q, testHandoffSigner, backend, sessions)(withTestPrincipal(finalizeCtx)); err != nil {
+			t.Fatalf("finalize: %v", err)
+		}
+		var resp map[string]string
+		if err := json.Unmarshal(finalizeRec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("parse finalize response: %v", err)
+		}
+		return resp
+	}
+
+	first := upload()
+	if first["deduped"] == "true" {
+		t.Fatalf("first upload should not be deduped")
+	}
+
+	// Drain and mark the first task Completed so it ages out of the
+	// queue's in-flight idempotency index, the scenario
+	// Store.FindCompletedByHash exists to still catch.
+	if _, ok := q.Dequeue(testCtx(t)); !ok {
+		t.Fatalf("expected first task to be enqueued")
+	}
+	q.Ack(first["task_id"])
+	if ok := s.UpdateTask(first["task_id"], func(tsk *store.Task) { tsk.Status = "Completed" }); !ok {
+		t.Fatalf("failed to mark first task completed")
+	}
+
+	second := upload()
+	if second["deduped"] != "true" {
+		t.Fatalf("expected second upload to be deduped, got %+v", second)
+	}
+	if second["task_id"] != first["task_id"] {
+		t.Fatalf("expected deduped upload to reuse task_id %s, got %s", first["task_id"], second["task_id"])
+	}
+}
@@ -0,0 +1,71 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var itemsBucket = []byte("queue_items")
+
+// BoltPersistence is the Persistence implementation NewDurableMemoryQueue
+// uses for single-node durability: every Put/Remove commits a bbolt
+// transaction before returning, mirroring store.BoltBackend.
+type BoltPersistence struct {
+	db *bolt.DB
+}
+
+// NewBoltPersistence opens (creating if needed) a bbolt database at path
+// and ensures the queue items bucket exists.
+func NewBoltPersistence(path string) (*BoltPersistence, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("open bolt queue %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(itemsBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init queue items bucket: %w", err)
+	}
+	return &BoltPersistence{db: db}, nil
+}
+
+// Close releases the underlying file lock.
+func (b *BoltPersistence) Close() error {
+	return b.db.Close()
+}
+
+func (b *BoltPersistence) Put(it Item) error {
+	raw, err := json.Marshal(it)
+	if err != nil {
+		return fmt.Errorf("encode queue item %s: %w", it.TaskID, err)
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(itemsBucket).Put([]byte(it.TaskID), raw)
+	})
+}
+
+func (b *BoltPersistence) Remove(taskID string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(itemsBucket).Delete([]byte(taskID))
+	})
+}
+
+func (b *BoltPersistence) Load() ([]Item, error) {
+	var out []Item
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(itemsBucket).ForEach(func(_, v []byte) error {
+			var it Item
+			if err := json.Unmarshal(v, &it); err != nil {
+				return err
+			}
+			out = append(out, it)
+			return nil
+		})
+	})
+	return out, err
+}
@@ -0,0 +1,87 @@
+package queue
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDurableMemoryQueueSurvivesRestart(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "queue.db")
+
+	persist, err := NewBoltPersistence(dbPath)
+	if err != nil {
+		t.Fatalf("new bolt persistence: %v", err)
+	}
+	q, err := NewDurableMemoryQueue(persist)
+	if err != nil {
+		t.Fatalf("new durable queue: %v", err)
+	}
+	q.Enqueue("t1", PriorityNormal, "hash-a")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	item, ok := q.Dequeue(ctx)
+	if !ok || item.TaskID != "t1" {
+		t.Fatalf("expected to dequeue t1, got %+v (ok=%v)", item, ok)
+	}
+	// t1 is left in-flight (never Acked), simulating a worker that died
+	// mid-task; restart below should redeliver it as ready rather than
+	// losing it.
+	if err := persist.Close(); err != nil {
+		t.Fatalf("close persistence: %v", err)
+	}
+
+	restarted, err := NewBoltPersistence(dbPath)
+	if err != nil {
+		t.Fatalf("reopen bolt persistence: %v", err)
+	}
+	defer restarted.Close()
+	q2, err := NewDurableMemoryQueue(restarted)
+	if err != nil {
+		t.Fatalf("new durable queue after restart: %v", err)
+	}
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), time.Second)
+	defer cancel2()
+	item2, ok := q2.Dequeue(ctx2)
+	if !ok || item2.TaskID != "t1" {
+		t.Fatalf("expected restarted queue to redeliver t1, got %+v (ok=%v)", item2, ok)
+	}
+
+	q2.Ack("t1")
+	if got := q2.Stats().Depth; got != 0 {
+		t.Fatalf("expected depth 0 after ack, got %d", got)
+	}
+}
+
+func TestDurableMemoryQueueAckRemovesPersistedItem(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "queue.db")
+
+	persist, err := NewBoltPersistence(dbPath)
+	if err != nil {
+		t.Fatalf("new bolt persistence: %v", err)
+	}
+	defer persist.Close()
+	q, err := NewDurableMemoryQueue(persist)
+	if err != nil {
+		t.Fatalf("new durable queue: %v", err)
+	}
+	q.Enqueue("t1", PriorityNormal, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, ok := q.Dequeue(ctx); !ok {
+		t.Fatalf("expected to dequeue t1")
+	}
+	q.Ack("t1")
+
+	items, err := persist.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("expected no persisted items after ack, got %+v", items)
+	}
+}
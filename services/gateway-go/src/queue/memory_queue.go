@@ -0,0 +1,328 @@
+package queue
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/telemetry"
+)
+
+// Persistence is an optional durability hook for MemoryQueue: every
+// state-changing call journals through it too, and NewDurableMemoryQueue
+// replays Load() at construction so a restart resumes with whatever was
+// ready, waiting, or in-flight rather than losing it. An item reloaded
+// from a crash mid-flight (Put but never Remove) comes back as ready,
+// which is the same redelivery a surviving process would have given it
+// once VisibilityTimeout elapsed; a reloaded item that was still in its
+// backoff window simply retries immediately rather than waiting out the
+// remainder, which is an acceptable tradeoff for losing at most one
+// backoff interval instead of the item itself.
+type Persistence interface {
+	Put(it Item) error
+	Remove(taskID string) error
+	Load() ([]Item, error)
+}
+
+const (
+	// MaxAttempts is how many times an item may be Nacked before it is
+	// moved to the dead-letter queue.
+	MaxAttempts = 5
+	// VisibilityTimeout bounds how long a dequeued item may go without
+	// an Ack/Nack before the reaper assumes its worker died and makes it
+	// ready again.
+	VisibilityTimeout = 2 * time.Minute
+)
+
+// baseBackoff/maxBackoff are vars rather than consts so tests can shrink
+// them instead of sleeping for real retry windows.
+var (
+	baseBackoff = 2 * time.Second
+	maxBackoff  = 2 * time.Minute
+)
+
+// MemoryQueue is an in-process priority queue. By default (NewMemoryQueue)
+// it is not durable across restarts; NewDurableMemoryQueue backs it with
+// a Persistence implementation (e.g. BoltPersistence) for deployments
+// that can't tolerate losing queued/in-flight work on a crash.
+type MemoryQueue struct {
+	mu sync.Mutex
+
+	ready   []Item // items eligible to be dequeued now
+	waiting []Item // items delayed by backoff (NotBefore in the future is tracked via readyAt)
+	readyAt map[string]time.Time
+
+	inFlight map[string]Item
+	deadline map[string]time.Time
+
+	seen map[string]string // idempotencyKey -> taskID, for items not yet Acked
+
+	deadLetter []Item
+
+	notify chan struct{}
+
+	persist Persistence // nil unless constructed via NewDurableMemoryQueue
+}
+
+// NewMemoryQueue constructs an empty, non-durable MemoryQueue and starts
+// its background reaper for visibility-timeout redelivery.
+func NewMemoryQueue() *MemoryQueue {
+	q := newMemoryQueue(nil)
+	go q.reapLoop()
+	return q
+}
+
+// NewDurableMemoryQueue constructs a MemoryQueue backed by persist:
+// every Enqueue/Ack/Nack/redelivery journals through persist, and
+// whatever persist.Load returns is replayed into the queue before this
+// returns, so a restart picks back up where the previous process left
+// off instead of silently dropping everything that was queued or
+// in-flight.
+func NewDurableMemoryQueue(persist Persistence) (*MemoryQueue, error) {
+	q := newMemoryQueue(persist)
+	items, err := persist.Load()
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range items {
+		if it.IdempotencyKey != "" {
+			q.seen[it.IdempotencyKey] = it.TaskID
+		}
+		q.insertReadyLocked(it)
+	}
+	q.reportDepthLocked()
+	go q.reapLoop()
+	return q, nil
+}
+
+func newMemoryQueue(persist Persistence) *MemoryQueue {
+	return &MemoryQueue{
+		readyAt:  make(map[string]time.Time),
+		inFlight: make(map[string]Item),
+		deadline: make(map[string]time.Time),
+		seen:     make(map[string]string),
+		notify:   make(chan struct{}, 1),
+		persist:  persist,
+	}
+}
+
+// persistPut journals it through q.persist, if configured, logging
+// rather than failing the caller: Queue's interface methods don't
+// return an error, and a durability hiccup shouldn't stop an otherwise
+// healthy in-memory enqueue/redelivery from proceeding.
+func (q *MemoryQueue) persistPut(it Item) {
+	if q.persist == nil {
+		return
+	}
+	if err := q.persist.Put(it); err != nil {
+		telemetry.L(context.Background()).Error().Err(err).Str("task_id", it.TaskID).Msg("queue: failed to persist item")
+	}
+}
+
+func (q *MemoryQueue) persistRemove(taskID string) {
+	if q.persist == nil {
+		return
+	}
+	if err := q.persist.Remove(taskID); err != nil {
+		telemetry.L(context.Background()).Error().Err(err).Str("task_id", taskID).Msg("queue: failed to remove persisted item")
+	}
+}
+
+func (q *MemoryQueue) Enqueue(taskID string, priority Priority, idempotencyKey string) (string, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if idempotencyKey != "" {
+		if existing, ok := q.seen[idempotencyKey]; ok && existing != taskID {
+			return existing, true
+		}
+		q.seen[idempotencyKey] = taskID
+	}
+
+	it := Item{
+		TaskID:         taskID,
+		Priority:       priority,
+		IdempotencyKey: idempotencyKey,
+		EnqueuedAt:     time.Now(),
+	}
+	q.insertReadyLocked(it)
+	q.persistPut(it)
+	q.reportDepthLocked()
+	q.wake()
+	return taskID, false
+}
+
+func (q *MemoryQueue) insertReadyLocked(it Item) {
+	q.ready = append(q.ready, it)
+	sort.SliceStable(q.ready, func(i, j int) bool {
+		return q.ready[i].Priority > q.ready[j].Priority
+	})
+}
+
+func (q *MemoryQueue) Dequeue(ctx context.Context) (Item, bool) {
+	for {
+		q.mu.Lock()
+		if len(q.ready) > 0 {
+			it := q.ready[0]
+			q.ready = q.ready[1:]
+			it.Attempts++
+			q.inFlight[it.TaskID] = it
+			q.deadline[it.TaskID] = time.Now().Add(VisibilityTimeout)
+			q.reportDepthLocked()
+			q.mu.Unlock()
+			return it, true
+		}
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return Item{}, false
+		case <-q.notify:
+		case <-time.After(500 * time.Millisecond):
+			// also wake periodically so delayed (backed-off) items that
+			// became ready get picked up without a fresh Enqueue/Nack
+		}
+		q.mu.Lock()
+		q.promoteDueLocked()
+		q.mu.Unlock()
+	}
+}
+
+// promoteDueLocked moves delayed items whose backoff has elapsed back
+// into the ready queue. Caller must hold q.mu.
+func (q *MemoryQueue) promoteDueLocked() {
+	now := time.Now()
+	remaining := q.waiting[:0]
+	for _, it := range q.waiting {
+		if !now.Before(q.readyAt[it.TaskID]) {
+			delete(q.readyAt, it.TaskID)
+			q.insertReadyLocked(it)
+		} else {
+			remaining = append(remaining, it)
+		}
+	}
+	q.waiting = remaining
+}
+
+func (q *MemoryQueue) Ack(taskID string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	it, ok := q.inFlight[taskID]
+	if ok {
+		delete(q.seen, it.IdempotencyKey)
+	}
+	delete(q.inFlight, taskID)
+	delete(q.deadline, taskID)
+	q.persistRemove(taskID)
+}
+
+func (q *MemoryQueue) Nack(taskID string, cause error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	it, ok := q.inFlight[taskID]
+	if !ok {
+		return
+	}
+	delete(q.inFlight, taskID)
+	delete(q.deadline, taskID)
+	if cause != nil {
+		it.LastError = cause.Error()
+	}
+
+	if it.Attempts >= MaxAttempts {
+		q.deadLetter = append(q.deadLetter, it)
+		delete(q.seen, it.IdempotencyKey)
+		q.persistRemove(taskID)
+		return
+	}
+
+	q.waiting = append(q.waiting, it)
+	q.readyAt[it.TaskID] = time.Now().Add(backoffWithJitter(it.Attempts))
+	q.persistPut(it)
+	q.reportDepthLocked()
+}
+
+// backoffWithJitter doubles baseBackoff per attempt, capped at
+// maxBackoff, with up to 20% random jitter so redelivered items don't
+// all retry in lockstep.
+func backoffWithJitter(attempt int) time.Duration {
+	d := baseBackoff
+	for i := 1; i < attempt && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(d) / 5))
+	return d + jitter
+}
+
+func (q *MemoryQueue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	oldest := time.Duration(0)
+	now := time.Now()
+	for _, it := range q.ready {
+		if age := now.Sub(it.EnqueuedAt); age > oldest {
+			oldest = age
+		}
+	}
+	return Stats{
+		Depth:          len(q.ready) + len(q.waiting),
+		InFlight:       len(q.inFlight),
+		OldestAge:      oldest,
+		DeadLetterSize: len(q.deadLetter),
+	}
+}
+
+func (q *MemoryQueue) DeadLetters() []Item {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]Item, len(q.deadLetter))
+	copy(out, q.deadLetter)
+	return out
+}
+
+// reportDepthLocked publishes the current ready+waiting depth to
+// Prometheus. Caller must hold q.mu.
+func (q *MemoryQueue) reportDepthLocked() {
+	telemetry.QueueDepth.Set(float64(len(q.ready) + len(q.waiting)))
+}
+
+func (q *MemoryQueue) wake() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// reapLoop redelivers items whose visibility timeout expired without an
+// Ack/Nack, which is what happens when the worker handling them crashes.
+func (q *MemoryQueue) reapLoop() {
+	ticker := time.NewTicker(VisibilityTimeout / 4)
+	defer ticker.Stop()
+	for range ticker.C {
+		q.mu.Lock()
+		now := time.Now()
+		for taskID, dl := range q.deadline {
+			if now.After(dl) {
+				it := q.inFlight[taskID]
+				delete(q.inFlight, taskID)
+				delete(q.deadline, taskID)
+				it.LastError = "visibility timeout: worker did not ack or nack"
+				if it.Attempts >= MaxAttempts {
+					q.deadLetter = append(q.deadLetter, it)
+					delete(q.seen, it.IdempotencyKey)
+					q.persistRemove(taskID)
+				} else {
+					q.insertReadyLocked(it)
+					q.persistPut(it)
+				}
+			}
+		}
+		q.mu.Unlock()
+		q.wake()
+	}
+}
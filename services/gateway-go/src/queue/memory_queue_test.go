@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEnqueueDedupesByIdempotencyKey(t *testing.T) {
+	q := NewMemoryQueue()
+	q.Enqueue("t1", PriorityNormal, "hash-a")
+	existing, deduped := q.Enqueue("t2", PriorityNormal, "hash-a")
+
+	if !deduped {
+		t.Fatalf("expected second enqueue with same key to be deduped")
+	}
+	if existing != "t1" {
+		t.Fatalf("expected existing task_id t1, got %s", existing)
+	}
+	if got := q.Stats().Depth; got != 1 {
+		t.Fatalf("expected depth 1, got %d", got)
+	}
+}
+
+func TestDequeueOrdersByPriority(t *testing.T) {
+	q := NewMemoryQueue()
+	q.Enqueue("low", PriorityNormal, "")
+	q.Enqueue("rush", PriorityRush, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	item, ok := q.Dequeue(ctx)
+	if !ok || item.TaskID != "rush" {
+		t.Fatalf("expected rush task first, got %+v (ok=%v)", item, ok)
+	}
+}
+
+func TestNackRequeuesThenDeadLettersAfterMaxAttempts(t *testing.T) {
+	old := baseBackoff
+	baseBackoff = time.Millisecond
+	defer func() { baseBackoff = old }()
+
+	q := NewMemoryQueue()
+	q.Enqueue("flaky", PriorityNormal, "hash-b")
+
+	// Dequeue promotes backed-off items on a 500ms poll, so the four
+	// redeliveries below take about 2s on their own.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < MaxAttempts; i++ {
+		item, ok := q.Dequeue(ctx)
+		if !ok {
+			t.Fatalf("attempt %d: expected item, dequeue failed", i)
+		}
+		q.Nack(item.TaskID, errors.New("boom"))
+	}
+
+	if stats := q.Stats(); stats.DeadLetterSize != 1 {
+		t.Fatalf("expected 1 dead-lettered item after %d attempts, got %+v", MaxAttempts, stats)
+	}
+	if _, ok := q.Dequeue(ctx); ok {
+		t.Fatalf("expected no further deliveries once dead-lettered")
+	}
+
+	dl := q.DeadLetters()
+	if len(dl) != 1 || dl[0].TaskID != "flaky" {
+		t.Fatalf("unexpected dead letters: %+v", dl)
+	}
+
+	// idempotency key should be released so a fresh upload of the same
+	// content isn't permanently blocked by a dead-lettered attempt.
+	existing, deduped := q.Enqueue("retry-task", PriorityNormal, "hash-b")
+	if deduped {
+		t.Fatalf("expected dead-lettered key to be re-enqueueable, got existing=%s", existing)
+	}
+}
+
+func TestAckClearsInFlightAndIdempotencyKey(t *testing.T) {
+	q := NewMemoryQueue()
+	q.Enqueue("t1", PriorityNormal, "hash-c")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	item, ok := q.Dequeue(ctx)
+	if !ok {
+		t.Fatalf("expected item")
+	}
+	q.Ack(item.TaskID)
+
+	if stats := q.Stats(); stats.Depth != 0 || stats.InFlight != 0 {
+		t.Fatalf("expected empty queue after ack, got %+v", stats)
+	}
+
+	// same content can be re-enqueued as a brand new task after ack
+	existing, deduped := q.Enqueue("t2", PriorityNormal, "hash-c")
+	if deduped || existing != "t2" {
+		t.Fatalf("expected fresh enqueue after ack, got existing=%s deduped=%v", existing, deduped)
+	}
+}
@@ -0,0 +1,66 @@
+// Package queue provides a durable-enough job queue abstraction sitting
+// in front of worker.Worker: priority ordering, idempotency dedup on a
+// caller-supplied key (the uploaded file's SHA-256), visibility-timeout
+// redelivery if a worker dies mid-task, exponential backoff with jitter
+// on failure, and a dead-letter queue once an item exceeds MaxAttempts.
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// Priority orders ready items; higher values are dequeued first.
+type Priority int
+
+const (
+	PriorityNormal Priority = 0
+	PriorityRush   Priority = 10
+)
+
+// Item is one unit of work tracked by a Queue.
+type Item struct {
+	TaskID         string
+	Priority       Priority
+	IdempotencyKey string
+	Attempts       int
+	EnqueuedAt     time.Time
+	LastError      string
+}
+
+// Stats summarizes queue health for GET /api/v1/queue/stats.
+type Stats struct {
+	Depth          int           `json:"depth"`
+	InFlight       int           `json:"in_flight"`
+	OldestAge      time.Duration `json:"oldest_age_ns"`
+	DeadLetterSize int           `json:"dead_letter_size"`
+}
+
+// Queue is the abstraction handlers.UploadHandler and worker.Worker are
+// wired against. MemoryQueue is the only implementation today; optionally
+// backed by BoltPersistence (NewDurableMemoryQueue) for single-node
+// durability, with Redis Streams/Asynq as the natural next backend once
+// the gateway needs to share a queue across replicas.
+type Queue interface {
+	// Enqueue admits taskID for processing. If idempotencyKey matches an
+	// item that is still queued, in-flight, or was already dequeued
+	// without a Nack, Enqueue does not add a duplicate and instead
+	// returns that item's TaskID with deduped=true.
+	Enqueue(taskID string, priority Priority, idempotencyKey string) (existingTaskID string, deduped bool)
+
+	// Dequeue blocks until an item is ready or ctx is done. The caller
+	// must eventually Ack or Nack the returned item's TaskID.
+	Dequeue(ctx context.Context) (Item, bool)
+
+	// Ack marks taskID as processed successfully, clearing its
+	// idempotency entry and in-flight tracking.
+	Ack(taskID string)
+
+	// Nack returns taskID to the queue with exponential backoff, unless
+	// MaxAttempts has been reached, in which case it moves to the
+	// dead-letter queue instead.
+	Nack(taskID string, cause error)
+
+	Stats() Stats
+	DeadLetters() []Item
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"html/template"
+	"io"
+)
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Audit report {{.TaskID}}</title></head>
+<body>
+<h1>Audit report {{.TaskID}}</h1>
+<p>Generated at {{.GeneratedAt}} &mdash; {{len .Issues}} issue(s)</p>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Rule</th><th>Severity</th><th>Location</th><th>Message</th><th>Suggested fix</th></tr>
+{{range .Issues}}<tr>
+<td>{{.RuleID}}</td>
+<td>{{.Severity}}</td>
+<td>section {{.Location.Section}} / paragraph {{.Location.Paragraph}} / run {{.Location.Run}}</td>
+<td>{{.Message}}</td>
+<td>{{.SuggestedFix}}</td>
+</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// HTMLRenderer produces a human-readable summary page for a Report.
+type HTMLRenderer struct{}
+
+func (HTMLRenderer) ContentType() string { return "text/html; charset=utf-8" }
+
+func (HTMLRenderer) Render(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, r)
+}
@@ -0,0 +1,18 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONRenderer pretty-prints the Report as-is; this is the format the
+// gateway has always returned from GET /api/v1/report/:id.
+type JSONRenderer struct{}
+
+func (JSONRenderer) ContentType() string { return "application/json" }
+
+func (JSONRenderer) Render(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
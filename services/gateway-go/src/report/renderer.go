@@ -0,0 +1,30 @@
+package report
+
+import "io"
+
+// Renderer turns a Report into a specific output format.
+type Renderer interface {
+	// ContentType is the MIME type to set on the HTTP response.
+	ContentType() string
+	// Render writes the formatted report to w.
+	Render(w io.Writer, r *Report) error
+}
+
+// renderers maps the `format` query param (and Accept-header short
+// names) to their Renderer. JSON stays the default so existing clients
+// of GET /api/v1/report/:id see no behavior change.
+var renderers = map[string]Renderer{
+	"json":  JSONRenderer{},
+	"sarif": SARIFRenderer{},
+	"html":  HTMLRenderer{},
+}
+
+// RendererFor looks up a Renderer by format name, defaulting to JSON
+// when format is empty. The bool return is false for an unknown format.
+func RendererFor(format string) (Renderer, bool) {
+	if format == "" {
+		format = "json"
+	}
+	r, ok := renderers[format]
+	return r, ok
+}
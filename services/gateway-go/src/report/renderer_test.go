@@ -0,0 +1,59 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func sampleReport() *Report {
+	return New("t1", []Issue{{
+		RuleID:   "citation-missing",
+		Severity: "error",
+		Location: Location{Section: 1, Paragraph: 2, Run: 3},
+		Message:  "missing citation",
+	}})
+}
+
+func TestJSONRendererRoundTrips(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONRenderer{}).Render(&buf, sampleReport()); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"rule_id": "citation-missing"`) {
+		t.Fatalf("expected rule_id in output, got: %s", buf.String())
+	}
+}
+
+func TestSARIFRendererIncludesRuleAndLocation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (SARIFRenderer{}).Render(&buf, sampleReport()); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `"ruleId": "citation-missing"`) {
+		t.Fatalf("expected ruleId in SARIF output, got: %s", out)
+	}
+	if !strings.Contains(out, `"level": "error"`) {
+		t.Fatalf("expected error level, got: %s", out)
+	}
+}
+
+func TestHTMLRendererListsIssues(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (HTMLRenderer{}).Render(&buf, sampleReport()); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(buf.String(), "citation-missing") {
+		t.Fatalf("expected issue in HTML output, got: %s", buf.String())
+	}
+}
+
+func TestRendererForUnknownFormat(t *testing.T) {
+	if _, ok := RendererFor("yaml"); ok {
+		t.Fatalf("expected unknown format to be rejected")
+	}
+	if _, ok := RendererFor(""); !ok {
+		t.Fatalf("expected empty format to default to json")
+	}
+}
@@ -0,0 +1,49 @@
+// Package report defines the structured audit report schema shared by
+// the worker (which populates it from the engine/inference responses)
+// and the HTTP handlers (which render it in whatever format a client
+// asked for).
+package report
+
+import "time"
+
+// Location pinpoints where an Issue was found within the source
+// document, down to the run inside a paragraph so a client can map it
+// back onto the original DOCX.
+type Location struct {
+	Section   int `json:"section"`
+	Paragraph int `json:"paragraph"`
+	Run       int `json:"run"`
+}
+
+// Issue is a single finding surfaced by the rules engine or the
+// semantic/inference service.
+type Issue struct {
+	RuleID       string   `json:"rule_id"`
+	Severity     string   `json:"severity"` // one of: info, warning, error
+	Location     Location `json:"location"`
+	Message      string   `json:"message"`
+	SuggestedFix string   `json:"suggested_fix,omitempty"`
+	Evidence     string   `json:"evidence,omitempty"`
+}
+
+// Report is the full result of auditing one document.
+type Report struct {
+	TaskID      string    `json:"task_id"`
+	Status      string    `json:"status"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Issues      []Issue   `json:"issues"`
+}
+
+// New builds a completed Report for taskID from the given issues,
+// stamping GeneratedAt at call time.
+func New(taskID string, issues []Issue) *Report {
+	if issues == nil {
+		issues = []Issue{}
+	}
+	return &Report{
+		TaskID:      taskID,
+		Status:      "completed",
+		GeneratedAt: time.Now(),
+		Issues:      issues,
+	}
+}
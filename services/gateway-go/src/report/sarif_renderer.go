@@ -0,0 +1,105 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// sarifLog is a minimal SARIF 2.1.0 document: one run, one tool, one
+// result per Issue. It deliberately omits the parts of the spec
+// (rule metadata, artifacts, fixes-as-replacements) this auditor
+// doesn't populate yet, so existing code-scanning UIs that only need
+// ruleId/level/message/location can already consume it.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// SARIFRenderer emits the report as a SARIF 2.1.0 log so it can be
+// consumed by existing code-scanning UIs.
+type SARIFRenderer struct{}
+
+func (SARIFRenderer) ContentType() string { return "application/sarif+json" }
+
+func (SARIFRenderer) Render(w io.Writer, r *Report) error {
+	results := make([]sarifResult, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		results = append(results, sarifResult{
+			RuleID:  issue.RuleID,
+			Level:   sarifLevel(issue.Severity),
+			Message: sarifMessage{Text: issue.Message},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name: sarifLocationName(issue.Location),
+					Kind: "paragraph",
+				}},
+			}},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "ai-auditor-core"}},
+			Results: results,
+		}},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(log)
+}
+
+// sarifLevel maps our severity vocabulary onto SARIF's ("note",
+// "warning", "error"); unrecognized severities fall back to "warning"
+// rather than being silently dropped.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "info":
+		return "note"
+	case "error":
+		return "error"
+	default:
+		return "warning"
+	}
+}
+
+func sarifLocationName(loc Location) string {
+	return "section " + strconv.Itoa(loc.Section) + "/paragraph " + strconv.Itoa(loc.Paragraph) + "/run " + strconv.Itoa(loc.Run)
+}
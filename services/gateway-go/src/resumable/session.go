@@ -0,0 +1,134 @@
+// Package resumable tracks in-progress chunked uploads so a large audit
+// input can survive a dropped client connection: the client creates a
+// session, PATCHes Content-Range chunks in order, each starting at the
+// session's current offset, and finalizes once every byte has arrived, without the gateway
+// ever holding the whole file in memory at once.
+package resumable
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// DefaultChunkSize is the chunk size CreateUploadSessionHandler advertises
+// to clients for their PATCHes. WriteChunk does not enforce it: a chunk of
+// any size is streamed to TempPath.
+const DefaultChunkSize = 8 << 20 // 8MB
+
+// Session tracks one in-progress resumable upload: the temp file its
+// chunks are appended to, and how many contiguous bytes have landed so
+// far.
+type Session struct {
+	ID       string
+	OwnerID  string
+	TempPath string
+
+	mu     sync.Mutex
+	offset int64
+	total  int64 // -1 until the first chunk's Content-Range reports it
+}
+
+// Offset returns the number of contiguous bytes written so far.
+func (s *Session) Offset() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.offset
+}
+
+// WriteChunk appends the bytes read from r to TempPath at [start, end],
+// rejecting anything that doesn't extend the upload contiguously from
+// the current offset: a replayed chunk (start < offset) or a gap
+// (start > offset) both indicate the client's retry logic got out of
+// sync with what the gateway actually has on disk.
+func (s *Session) WriteChunk(start, end, total int64, r io.Reader) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if start != s.offset {
+		return fmt.Errorf("resumable: expected chunk starting at offset %d, got %d", s.offset, start)
+	}
+	if s.total != -1 && s.total != total {
+		return fmt.Errorf("resumable: total size changed mid-upload (was %d, now %d)", s.total, total)
+	}
+	s.total = total
+
+	f, err := os.OpenFile(s.TempPath, os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		return err
+	}
+	n, err := io.Copy(f, io.LimitReader(r, end-start+1))
+	s.offset += n
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Total returns the size the client reported in its first Content-Range
+// header, or -1 if no chunk has landed yet.
+func (s *Session) Total() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.total
+}
+
+// Manager creates and looks up Sessions, each backed by its own temp
+// file under dir.
+type Manager struct {
+	dir string
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewManager constructs a Manager whose session temp files live under
+// dir, creating it if necessary.
+func NewManager(dir string) (*Manager, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Manager{dir: dir, sessions: make(map[string]*Session)}, nil
+}
+
+// Create starts a new session owned by ownerID and returns it.
+func (m *Manager) Create(ownerID string) (*Session, error) {
+	id := uuid.New().String()
+	tempPath := filepath.Join(m.dir, id+".part")
+	f, err := os.Create(tempPath)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+
+	sess := &Session{ID: id, OwnerID: ownerID, TempPath: tempPath, total: -1}
+	m.mu.Lock()
+	m.sessions[id] = sess
+	m.mu.Unlock()
+	return sess, nil
+}
+
+// Get returns the session with the given ID, if any.
+func (m *Manager) Get(id string) (*Session, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sess, ok := m.sessions[id]
+	return sess, ok
+}
+
+// Delete forgets a session, e.g. after it has been finalized into a
+// task's SourcePath. It does not remove TempPath: the caller does that
+// (FinalizeUploadSessionHandler removes it once backend.Put has copied it).
+func (m *Manager) Delete(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+}
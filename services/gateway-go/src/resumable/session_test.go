@@ -0,0 +1,86 @@
+package resumable
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestSessionWriteChunkContiguous(t *testing.T) {
+	m, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	sess, err := m.Create("tenant-a")
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	content := []byte("0123456789")
+	if err := sess.WriteChunk(0, 4, int64(len(content)), bytes.NewReader(content[0:5])); err != nil {
+		t.Fatalf("write first chunk: %v", err)
+	}
+	if got := sess.Offset(); got != 5 {
+		t.Fatalf("expected offset 5, got %d", got)
+	}
+
+	if err := sess.WriteChunk(5, 9, int64(len(content)), bytes.NewReader(content[5:10])); err != nil {
+		t.Fatalf("write second chunk: %v", err)
+	}
+	if got := sess.Offset(); got != 10 {
+		t.Fatalf("expected offset 10, got %d", got)
+	}
+
+	got, err := os.ReadFile(sess.TempPath)
+	if err != nil {
+		t.Fatalf("read temp file: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("expected %q, got %q", content, got)
+	}
+}
+
+func TestSessionWriteChunkRejectsNonContiguous(t *testing.T) {
+	m, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	sess, err := m.Create("tenant-a")
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	if err := sess.WriteChunk(0, 3, 10, bytes.NewReader([]byte("abcd"))); err != nil {
+		t.Fatalf("write first chunk: %v", err)
+	}
+
+	// A replay of the same range should be rejected, not silently
+	// accepted or re-applied.
+	if err := sess.WriteChunk(0, 3, 10, bytes.NewReader([]byte("abcd"))); err == nil {
+		t.Fatalf("expected error replaying chunk")
+	}
+
+	// A gap past the current offset should also be rejected.
+	if err := sess.WriteChunk(5, 9, 10, bytes.NewReader([]byte("fghij"))); err == nil {
+		t.Fatalf("expected error on non-contiguous chunk")
+	}
+}
+
+func TestManagerGetAndDelete(t *testing.T) {
+	m, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	sess, err := m.Create("tenant-a")
+	if err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+
+	if _, ok := m.Get(sess.ID); !ok {
+		t.Fatalf("expected to find session")
+	}
+	m.Delete(sess.ID)
+	if _, ok := m.Get(sess.ID); ok {
+		t.Fatalf("expected session to be gone after delete")
+	}
+}
@@ -0,0 +1,111 @@
+//go:build grpc
+// +build grpc
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// ErrCircuitOpen is returned in place of dialing/calling an upstream
+// whose circuit breaker has tripped.
+var ErrCircuitOpen = errors.New("rpcclient: circuit breaker open")
+
+// CircuitBreaker fails fast once consecutiveFailureThreshold calls in a
+// row have failed, instead of letting every caller queue up behind a
+// dead upstream's timeout. After openDuration it goes half-open and lets
+// calls through again; it does not limit them to a single probe. The next
+// recorded success closes the breaker, the next tracked failure reopens it.
+type CircuitBreaker struct {
+	mu                          sync.Mutex
+	state                       breakerState
+	consecutiveFailures         int
+	consecutiveFailureThreshold int
+	openDuration                time.Duration
+	openedAt                    time.Time
+}
+
+// NewCircuitBreaker returns a breaker that opens after 5 consecutive
+// failures and stays open for 10s before probing again.
+func NewCircuitBreaker() *CircuitBreaker {
+	return &CircuitBreaker{
+		consecutiveFailureThreshold: 5,
+		openDuration:                10 * time.Second,
+	}
+}
+
+// UnaryClientInterceptor rejects calls outright while the breaker is
+// open, and otherwise records the outcome of the call to drive state
+// transitions.
+func (b *CircuitBreaker) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !b.allow() {
+			return status.Error(codes.Unavailable, ErrCircuitOpen.Error())
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		b.record(err)
+		return err
+	}
+}
+
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.openDuration {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.consecutiveFailures = 0
+		b.state = breakerClosed
+		return
+	}
+
+	st, ok := status.FromError(err)
+	if !ok || !retryableCodes[st.Code()] {
+		// Not the kind of failure the breaker tracks (e.g. InvalidArgument) -
+		// a bad request doesn't mean the upstream is unhealthy.
+		return
+	}
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		return
+	}
+
+	b.consecutiveFailures++
+	if b.consecutiveFailures >= b.consecutiveFailureThreshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,50 @@
+//go:build grpc
+// +build grpc
+
+package rpcclient
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b := NewCircuitBreaker()
+	b.consecutiveFailureThreshold = 3
+
+	for i := 0; i < 3; i++ {
+		if !b.allow() {
+			t.Fatalf("expected breaker to allow call %d before tripping", i)
+		}
+		b.record(status.Error(codes.Unavailable, "boom"))
+	}
+
+	if b.allow() {
+		t.Fatal("expected breaker to be open after consecutive failures")
+	}
+}
+
+func TestCircuitBreakerIgnoresNonRetryableErrors(t *testing.T) {
+	b := NewCircuitBreaker()
+	b.consecutiveFailureThreshold = 1
+
+	b.record(status.Error(codes.InvalidArgument, "bad request"))
+
+	if !b.allow() {
+		t.Fatal("expected breaker to stay closed for a non-retryable error")
+	}
+}
+
+func TestCircuitBreakerClosesOnSuccess(t *testing.T) {
+	b := NewCircuitBreaker()
+	b.consecutiveFailureThreshold = 2
+
+	b.record(status.Error(codes.Unavailable, "boom"))
+	b.record(nil)
+
+	if b.consecutiveFailures != 0 || b.state != breakerClosed {
+		t.Fatalf("expected a success to reset the breaker, got state=%v failures=%d", b.state, b.consecutiveFailures)
+	}
+}
@@ -0,0 +1,143 @@
+//go:build grpc
+// +build grpc
+
+// Package rpcclient maintains long-lived, pooled gRPC connections to the
+// parser/engine/inference upstreams, replacing the worker's old habit of
+// dialing a fresh *grpc.ClientConn per task per service. Connections are
+// built once per upstream name with keepalive, optional mTLS, retry on
+// transient errors, and a circuit breaker that fails fast once an
+// upstream looks unhealthy.
+package rpcclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+)
+
+// UpstreamConfig describes how to reach and authenticate to one gRPC
+// upstream (parser-rs, engine-java, or inference-py).
+type UpstreamConfig struct {
+	Name string // used as the circuit-breaker and metrics label
+	Addr string
+
+	// CACertPath, if set, enables mTLS: the gateway verifies the
+	// upstream's certificate against this CA and presents its own via
+	// ClientCertPath/ClientKeyPath.
+	CACertPath     string
+	ClientCertPath string
+	ClientKeyPath  string
+}
+
+// Pool hands out a pooled *grpc.ClientConn per upstream, dialing lazily
+// and caching the result so repeated calls reuse one long-lived
+// connection instead of paying a fresh handshake per task.
+type Pool struct {
+	mu       sync.Mutex
+	conns    map[string]*grpc.ClientConn
+	breakers map[string]*CircuitBreaker
+}
+
+// NewPool constructs an empty connection pool.
+func NewPool() *Pool {
+	return &Pool{
+		conns:    make(map[string]*grpc.ClientConn),
+		breakers: make(map[string]*CircuitBreaker),
+	}
+}
+
+// Conn returns the pooled connection for cfg, dialing it on first use.
+func (p *Pool) Conn(ctx context.Context, cfg UpstreamConfig) (*grpc.ClientConn, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if conn, ok := p.conns[cfg.Name]; ok {
+		return conn, nil
+	}
+
+	creds, err := transportCredentials(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("rpcclient: build credentials for %s: %w", cfg.Name, err)
+	}
+
+	breaker := NewCircuitBreaker()
+	p.breakers[cfg.Name] = breaker
+
+	conn, err := grpc.DialContext(ctx, cfg.Addr,
+		creds,
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                20 * time.Second,
+			Timeout:             5 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithChainUnaryInterceptor(
+			otelgrpc.UnaryClientInterceptor(),
+			breaker.UnaryClientInterceptor(),
+			RetryUnaryClientInterceptor(3),
+		),
+	)
+	if err != nil {
+		delete(p.breakers, cfg.Name)
+		return nil, fmt.Errorf("rpcclient: dial %s (%s): %w", cfg.Name, cfg.Addr, err)
+	}
+
+	p.conns[cfg.Name] = conn
+	return conn, nil
+}
+
+// Close tears down every pooled connection; call it once on shutdown.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var firstErr error
+	for name, conn := range p.conns {
+		if err := conn.Close(); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("close %s: %w", name, err)
+		}
+	}
+	return firstErr
+}
+
+func transportCredentials(cfg UpstreamConfig) (grpc.DialOption, error) {
+	if cfg.CACertPath == "" {
+		return grpc.WithTransportCredentials(insecure.NewCredentials()), nil
+	}
+
+	caPEM, err := os.ReadFile(cfg.CACertPath)
+	if err != nil {
+		return nil, fmt.Errorf("read CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", cfg.CACertPath)
+	}
+
+	clientCert, err := tls.LoadX509KeyPair(cfg.ClientCertPath, cfg.ClientKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("load client keypair: %w", err)
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{clientCert},
+	})), nil
+}
+
+// retryableCodes are the statuses worth retrying: the upstream is
+// transiently unreachable or the call ran past its deadline, not cases
+// where retrying would just repeat a bad request.
+var retryableCodes = map[codes.Code]bool{
+	codes.Unavailable:      true,
+	codes.DeadlineExceeded: true,
+}
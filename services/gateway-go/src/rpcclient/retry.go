@@ -0,0 +1,56 @@
+//go:build grpc
+// +build grpc
+
+package rpcclient
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// RetryUnaryClientInterceptor retries a unary call up to maxAttempts
+// times (the original call plus maxAttempts-1 retries) when it fails
+// with a retryableCodes status, backing off with jitter so a flapping
+// upstream doesn't get hammered by every worker goroutine in lockstep.
+func RetryUnaryClientInterceptor(maxAttempts int) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var lastErr error
+		for attempt := 0; attempt < maxAttempts; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-time.After(retryBackoff(attempt)):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+
+			lastErr = invoker(ctx, method, req, reply, cc, opts...)
+			if lastErr == nil {
+				return nil
+			}
+
+			st, ok := status.FromError(lastErr)
+			if !ok || !retryableCodes[st.Code()] {
+				return lastErr
+			}
+		}
+		return lastErr
+	}
+}
+
+// retryBackoff returns an exponential delay (100ms, 200ms, 400ms, ...)
+// plus up to 50% jitter, capped at 2s so a deadline-bound caller doesn't
+// lose its whole budget to backoff.
+func retryBackoff(attempt int) time.Duration {
+	base := 100 * time.Millisecond
+	delay := base << uint(attempt-1)
+	if delay > 2*time.Second {
+		delay = 2 * time.Second
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) / 2))
+	return delay + jitter
+}
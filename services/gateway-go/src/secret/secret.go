@@ -0,0 +1,109 @@
+// Package secret loads and caches the shared signing key used for the
+// short-lived JWT handoff between UploadHandler and the worker pool, so
+// a worker can prove the task it's about to process (and the file path
+// and hash it's about to touch) is actually what the gateway enqueued.
+package secret
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultTTL is how long an issued handoff token remains valid.
+const DefaultTTL = 60 * time.Second
+
+var (
+	mu     sync.Mutex
+	cached []byte
+	loaded bool
+)
+
+// Load reads the secret at path once and caches it in memory, so
+// repeated Issue/Verify calls don't re-read the file from disk.
+func Load(path string) ([]byte, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if loaded {
+		return cached, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("secret: read %q: %w", path, err)
+	}
+	cached = bytes.TrimSpace(data)
+	loaded = true
+	return cached, nil
+}
+
+// Reset clears the in-memory cache. Only tests should need this, to
+// point Load at a different fixture path across cases.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	cached = nil
+	loaded = false
+}
+
+// HandoffClaims identify the task, file, and content an issued token
+// authorizes a worker to act on.
+type HandoffClaims struct {
+	TaskID   string `json:"task_id"`
+	FilePath string `json:"file_path"`
+	SHA256   string `json:"sha256"`
+	jwt.RegisteredClaims
+}
+
+// HandoffSigner issues and verifies HandoffClaims tokens against a
+// single shared key, mirroring auth.URLSigner's Sign/Verify shape.
+type HandoffSigner struct {
+	key []byte
+}
+
+// NewHandoffSigner builds a HandoffSigner over key, typically the
+// result of Load.
+func NewHandoffSigner(key []byte) *HandoffSigner {
+	return &HandoffSigner{key: key}
+}
+
+// Issue signs a HandoffClaims token for taskID/filePath/sha256, valid
+// for ttl (DefaultTTL if ttl is zero or negative).
+func (s *HandoffSigner) Issue(taskID, filePath, sha256 string, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	now := time.Now()
+	claims := HandoffClaims{
+		TaskID:   taskID,
+		FilePath: filePath,
+		SHA256:   sha256,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.key)
+}
+
+// Verify checks tokenString's signature and expiry. It rejects any
+// token not signed with HMAC even if the signature would otherwise
+// validate against s.key, since accepting the algorithm named in the
+// token's own header is how "alg: none" and cross-algorithm confusion
+// attacks forge a token without ever knowing the key.
+func (s *HandoffSigner) Verify(tokenString string) (*HandoffClaims, error) {
+	claims := &HandoffClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("secret: unexpected signing method %v", token.Header["alg"])
+		}
+		return s.key, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("secret: invalid handoff token: %w", err)
+	}
+	return claims, nil
+}
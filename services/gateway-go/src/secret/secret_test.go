@@ -0,0 +1,86 @@
+package secret
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestHandoffSignerRoundTrip(t *testing.T) {
+	signer := NewHandoffSigner([]byte("test-secret"))
+	token, err := signer.Issue("task-1", "/tmp/doc.docx", "deadbeef", time.Minute)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	claims, err := signer.Verify(token)
+	if err != nil {
+		t.Fatalf("expected token to verify, got: %v", err)
+	}
+	if claims.TaskID != "task-1" || claims.FilePath != "/tmp/doc.docx" || claims.SHA256 != "deadbeef" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestHandoffSignerRejectsUnexpectedSigningMethod(t *testing.T) {
+	signer := NewHandoffSigner([]byte("test-secret"))
+
+	// A token whose header claims "none" must be rejected even though
+	// Verify is handed the real key, since trusting the algorithm the
+	// token names itself is exactly what lets an attacker forge one.
+	claims := HandoffClaims{
+		TaskID:   "task-1",
+		FilePath: "/tmp/doc.docx",
+		SHA256:   "deadbeef",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	unsigned, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign none token: %v", err)
+	}
+
+	if _, err := signer.Verify(unsigned); err == nil {
+		t.Fatalf("expected a non-HMAC token to be rejected")
+	}
+}
+
+func TestHandoffSignerRejectsExpiredToken(t *testing.T) {
+	signer := NewHandoffSigner([]byte("test-secret"))
+	// Issue treats a non-positive ttl as DefaultTTL, so the expired token
+	// is signed here directly with the same key.
+	now := time.Now()
+	claims := HandoffClaims{
+		TaskID:   "task-1",
+		FilePath: "/tmp/doc.docx",
+		SHA256:   "deadbeef",
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now.Add(-2 * time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(-time.Minute)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatalf("sign expired token: %v", err)
+	}
+
+	if _, err := signer.Verify(token); err == nil {
+		t.Fatalf("expected an expired token to fail verification")
+	}
+}
+
+func TestHandoffSignerRejectsTamperedSecret(t *testing.T) {
+	signerA := NewHandoffSigner([]byte("secret-a"))
+	signerB := NewHandoffSigner([]byte("secret-b"))
+	token, err := signerA.Issue("task-1", "/tmp/doc.docx", "deadbeef", time.Minute)
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	if _, err := signerB.Verify(token); err == nil {
+		t.Fatalf("expected a token signed with a different secret to fail verification")
+	}
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalFS is the default Backend: every key is a path under root on the
+// local disk, preserving the gateway's original single-replica
+// behavior.
+type LocalFS struct {
+	root string
+}
+
+// NewLocalFS constructs a LocalFS rooted at dir, creating it if it
+// doesn't already exist.
+func NewLocalFS(dir string) (*LocalFS, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalFS{root: dir}, nil
+}
+
+func (b *LocalFS) Put(ctx context.Context, key string, r io.Reader) (string, int64, error) {
+	path := filepath.Join(b.root, key)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	n, err := io.Copy(f, r)
+	if err != nil {
+		return "", 0, err
+	}
+	return path, n, nil
+}
+
+// Open takes the exact path a prior Put returned, not a bare key
+// rejoined against root, so the caller never needs to know root.
+func (b *LocalFS) Open(ctx context.Context, path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotExist
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (b *LocalFS) Delete(ctx context.Context, path string) error {
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+func (b *LocalFS) Stat(ctx context.Context, path string) (Stat, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Stat{}, ErrNotExist
+		}
+		return Stat{}, err
+	}
+	return Stat{Size: info.Size(), ModTime: info.ModTime()}, nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+)
+
+func TestLocalFSPutOpenRoundTrip(t *testing.T) {
+	b, err := NewLocalFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local fs: %v", err)
+	}
+	ctx := context.Background()
+
+	url, size, err := b.Put(ctx, "doc.docx", bytes.NewReader([]byte("hello world")))
+	if err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if size != 11 {
+		t.Fatalf("expected size 11, got %d", size)
+	}
+
+	rc, err := b.Open(ctx, url)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer rc.Close()
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(got) != "hello world" {
+		t.Fatalf("expected %q, got %q", "hello world", got)
+	}
+
+	stat, err := b.Stat(ctx, url)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if stat.Size != 11 {
+		t.Fatalf("expected stat size 11, got %d", stat.Size)
+	}
+
+	if err := b.Delete(ctx, url); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := b.Open(ctx, url); err != ErrNotExist {
+		t.Fatalf("expected ErrNotExist after delete, got %v", err)
+	}
+}
+
+func TestLocalFSOpenMissingKey(t *testing.T) {
+	b, err := NewLocalFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local fs: %v", err)
+	}
+	if _, err := b.Open(context.Background(), "nope.docx"); err != ErrNotExist {
+		t.Fatalf("expected ErrNotExist, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+//go:build s3
+// +build s3
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// NOTE: aws-sdk-go-v2 isn't vendored into this tree, so the imports
+// above are assumed rather than checked in; build with -tags s3 once
+// it's added to the module.
+
+func init() {
+	registerDriver("s3", newS3)
+}
+
+// multipartThreshold is the PartSize handed to manager.Uploader, which
+// transparently switches from a single PutObject to a multipart upload
+// once it reads past this many bytes.
+const multipartThreshold = 5 << 20 // 5MB
+
+// s3Backend puts/gets objects in a single bucket, using the SDK's
+// managed uploader so Put doesn't need to branch on input size itself.
+type s3Backend struct {
+	bucket   string
+	client   *s3.Client
+	uploader *manager.Uploader
+}
+
+func newS3(cfg Config) (Backend, error) {
+	if cfg.S3Bucket == "" {
+		return nil, fmt.Errorf("storage: STORAGE_S3_BUCKET is required for the s3 driver")
+	}
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(cfg.S3Region))
+	if err != nil {
+		return nil, fmt.Errorf("storage: load aws config: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg)
+	return &s3Backend{
+		bucket: cfg.S3Bucket,
+		client: client,
+		uploader: manager.NewUploader(client, func(u *manager.Uploader) {
+			u.PartSize = multipartThreshold
+		}),
+	}, nil
+}
+
+func (b *s3Backend) Put(ctx context.Context, key string, r io.Reader) (string, int64, error) {
+	counting := &countingReader{r: r}
+	if _, err := b.uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+		Body:   counting,
+	}); err != nil {
+		return "", 0, fmt.Errorf("storage: s3 put %q: %w", key, err)
+	}
+	return fmt.Sprintf("s3://%s/%s", b.bucket, key), counting.n, nil
+}
+
+func (b *s3Backend) Open(ctx context.Context, url string) (io.ReadCloser, error) {
+	key, err := b.keyFromURL(url)
+	if err != nil {
+		return nil, err
+	}
+	out, err := b.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(b.bucket), Key: aws.String(key)})
+	if err != nil {
+		return nil, fmt.Errorf("storage: s3 open %q: %w", url, err)
+	}
+	return out.Body, nil
+}
+
+func (b *s3Backend) Delete(ctx context.Context, url string) error {
+	key, err := b.keyFromURL(url)
+	if err != nil {
+		return err
+	}
+	if _, err := b.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(b.bucket), Key: aws.String(key)}); err != nil {
+		return fmt.Errorf("storage: s3 delete %q: %w", url, err)
+	}
+	return nil
+}
+
+func (b *s3Backend) Stat(ctx context.Context, url string) (Stat, error) {
+	key, err := b.keyFromURL(url)
+	if err != nil {
+		return Stat{}, err
+	}
+	out, err := b.client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String(b.bucket), Key: aws.String(key)})
+	if err != nil {
+		return Stat{}, fmt.Errorf("storage: s3 stat %q: %w", url, err)
+	}
+	var modTime time.Time
+	if out.LastModified != nil {
+		modTime = *out.LastModified
+	}
+	return Stat{Size: aws.ToInt64(out.ContentLength), ModTime: modTime}, nil
+}
+
+// keyFromURL recovers the object key from the "s3://bucket/key" string
+// Put returned, so callers can hand that value straight back to
+// Open/Delete/Stat without tracking the bucket themselves.
+func (b *s3Backend) keyFromURL(url string) (string, error) {
+	prefix := "s3://" + b.bucket + "/"
+	if !strings.HasPrefix(url, prefix) {
+		return "", fmt.Errorf("storage: %q is not an s3 object in bucket %q", url, b.bucket)
+	}
+	return strings.TrimPrefix(url, prefix), nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
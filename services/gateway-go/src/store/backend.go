@@ -0,0 +1,20 @@
+package store
+
+// Backend is the persistence contract for task state. Implementations must
+// make UpdateTask atomic with respect to concurrent callers so that two
+// workers racing on the same task ID cannot silently clobber each other's
+// writes (see Task.Version).
+type Backend interface {
+	AddTask(t *Task) error
+	GetTask(id string) (*Task, bool, error)
+	UpdateTask(id string, fn func(*Task)) (bool, error)
+	List() ([]*Task, error)
+	Delete(id string) error
+
+	// CompareAndSwap applies fn only if the stored task's Version still
+	// equals expectedVersion, returning false without error if it has
+	// moved on. Callers use this for optimistic-concurrency transitions
+	// (e.g. claiming a task for recovery) without holding a lock across
+	// the whole operation.
+	CompareAndSwap(id string, expectedVersion uint64, fn func(*Task)) (bool, error)
+}
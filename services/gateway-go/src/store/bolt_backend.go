@@ -0,0 +1,135 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var tasksBucket = []byte("tasks")
+
+// BoltBackend is the embedded, single-node durable backend: every AddTask
+// and UpdateTask commits a bbolt transaction before returning, so a
+// gateway restart can recover whatever was last durably written instead
+// of losing in-flight jobs.
+type BoltBackend struct {
+	db *bolt.DB
+}
+
+// NewBoltBackend opens (creating if needed) a bbolt database at path and
+// ensures the tasks bucket exists.
+func NewBoltBackend(path string) (*BoltBackend, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("open bolt store %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(tasksBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("init tasks bucket: %w", err)
+	}
+	return &BoltBackend{db: db}, nil
+}
+
+// Close releases the underlying file lock.
+func (b *BoltBackend) Close() error {
+	return b.db.Close()
+}
+
+func (b *BoltBackend) AddTask(t *Task) error {
+	t.Version = 1
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return putTask(tx, t)
+	})
+}
+
+func (b *BoltBackend) GetTask(id string) (*Task, bool, error) {
+	var t *Task
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		t, err = getTask(tx, id)
+		return err
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return t, t != nil, nil
+}
+
+func (b *BoltBackend) UpdateTask(id string, fn func(*Task)) (bool, error) {
+	found := false
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		t, err := getTask(tx, id)
+		if err != nil {
+			return err
+		}
+		if t == nil {
+			return nil
+		}
+		found = true
+		fn(t)
+		t.Version++
+		return putTask(tx, t)
+	})
+	return found, err
+}
+
+func (b *BoltBackend) List() ([]*Task, error) {
+	var out []*Task
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(tasksBucket).ForEach(func(_, v []byte) error {
+			var t Task
+			if err := json.Unmarshal(v, &t); err != nil {
+				return err
+			}
+			out = append(out, &t)
+			return nil
+		})
+	})
+	return out, err
+}
+
+func (b *BoltBackend) Delete(id string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(tasksBucket).Delete([]byte(id))
+	})
+}
+
+func (b *BoltBackend) CompareAndSwap(id string, expectedVersion uint64, fn func(*Task)) (bool, error) {
+	swapped := false
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		t, err := getTask(tx, id)
+		if err != nil || t == nil || t.Version != expectedVersion {
+			return err
+		}
+		fn(t)
+		t.Version++
+		swapped = true
+		return putTask(tx, t)
+	})
+	return swapped, err
+}
+
+func getTask(tx *bolt.Tx, id string) (*Task, error) {
+	raw := tx.Bucket(tasksBucket).Get([]byte(id))
+	if raw == nil {
+		return nil, nil
+	}
+	var t Task
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return nil, fmt.Errorf("decode task %s: %w", id, err)
+	}
+	return &t, nil
+}
+
+func putTask(tx *bolt.Tx, t *Task) error {
+	raw, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("encode task %s: %w", t.ID, err)
+	}
+	return tx.Bucket(tasksBucket).Put([]byte(t.ID), raw)
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config selects and configures the Store's Backend via STORE_DRIVER,
+// mirroring storage.Config/storage.ConfigFromEnv.
+type Config struct {
+	Driver   string // "memory" (default) or "bolt"
+	BoltPath string
+}
+
+// ConfigFromEnv reads STORE_DRIVER (default "memory") and STORE_BOLT_PATH.
+func ConfigFromEnv() Config {
+	return Config{
+		Driver:   getenvDefault("STORE_DRIVER", "memory"),
+		BoltPath: getenvDefault("STORE_BOLT_PATH", filepath.Join("..", "temp_docs", "store.db")),
+	}
+}
+
+// New builds a Store from cfg. The "bolt" driver gives RecoverInterrupted
+// something real to recover on a restart; "memory" (the default)
+// preserves the original zero-config behavior for local runs and tests.
+func New(cfg Config) (*Store, error) {
+	driver := cfg.Driver
+	if driver == "" {
+		driver = "memory"
+	}
+	switch driver {
+	case "memory":
+		return NewStore(), nil
+	case "bolt":
+		b, err := NewBoltBackend(cfg.BoltPath)
+		if err != nil {
+			return nil, err
+		}
+		return NewStoreWithBackend(b), nil
+	default:
+		return nil, fmt.Errorf("store: unknown driver %q", driver)
+	}
+}
+
+func getenvDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
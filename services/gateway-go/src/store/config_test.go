@@ -0,0 +1,32 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNewSelectsDriver(t *testing.T) {
+	s, err := New(Config{Driver: "memory"})
+	if err != nil {
+		t.Fatalf("memory driver: %v", err)
+	}
+	if err := s.AddTask(&Task{ID: "t1", Status: "Pending"}); err != nil {
+		t.Fatalf("add task: %v", err)
+	}
+
+	boltPath := filepath.Join(t.TempDir(), "store.db")
+	s, err = New(Config{Driver: "bolt", BoltPath: boltPath})
+	if err != nil {
+		t.Fatalf("bolt driver: %v", err)
+	}
+	if err := s.AddTask(&Task{ID: "t1", Status: "Pending"}); err != nil {
+		t.Fatalf("add task: %v", err)
+	}
+	if _, ok := s.GetTask("t1"); !ok {
+		t.Fatalf("expected task to round-trip through bolt backend")
+	}
+
+	if _, err := New(Config{Driver: "bogus"}); err == nil {
+		t.Fatalf("expected error for unknown driver")
+	}
+}
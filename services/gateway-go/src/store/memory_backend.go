@@ -0,0 +1,72 @@
+package store
+
+import "sync"
+
+// MemoryBackend keeps tasks in a map guarded by a mutex. It satisfies
+// Backend but, like the original Store, loses all state on process
+// restart; it exists for tests and single-process local runs.
+type MemoryBackend struct {
+	mu    sync.RWMutex
+	tasks map[string]*Task
+}
+
+// NewMemoryBackend constructs an empty in-memory backend.
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{tasks: make(map[string]*Task)}
+}
+
+func (b *MemoryBackend) AddTask(t *Task) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t.Version = 1
+	b.tasks[t.ID] = t
+	return nil
+}
+
+func (b *MemoryBackend) GetTask(id string) (*Task, bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	t, ok := b.tasks[id]
+	return t, ok, nil
+}
+
+func (b *MemoryBackend) UpdateTask(id string, fn func(*Task)) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.tasks[id]
+	if !ok {
+		return false, nil
+	}
+	fn(t)
+	t.Version++
+	return true, nil
+}
+
+func (b *MemoryBackend) List() ([]*Task, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	out := make([]*Task, 0, len(b.tasks))
+	for _, t := range b.tasks {
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func (b *MemoryBackend) Delete(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.tasks, id)
+	return nil
+}
+
+func (b *MemoryBackend) CompareAndSwap(id string, expectedVersion uint64, fn func(*Task)) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.tasks[id]
+	if !ok || t.Version != expectedVersion {
+		return false, nil
+	}
+	fn(t)
+	t.Version++
+	return true, nil
+}
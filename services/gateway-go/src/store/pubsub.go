@@ -0,0 +1,79 @@
+package store
+
+import "sync"
+
+// Update is a snapshot pushed whenever a task's status/progress changes.
+// Seq is sent as each SSE event's id; nothing reads it back when a client
+// reconnects.
+type Update struct {
+	Seq      uint64 `json:"seq"`
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	Stage    string `json:"stage"`
+}
+
+// subscriberBuffer bounds how far a slow consumer can fall behind before
+// we drop each new update for it rather than blocking the publisher.
+const subscriberBuffer = 32
+
+// pubsub fans out task updates to per-task subscribers. It is embedded in
+// Store so every UpdateTask call site (worker and handlers alike) gets
+// push notifications for free, without each caller having to know about
+// streaming.
+type pubsub struct {
+	mu   sync.Mutex
+	seq  uint64
+	subs map[string]map[chan Update]struct{}
+}
+
+func newPubsub() *pubsub {
+	return &pubsub{subs: make(map[string]map[chan Update]struct{})}
+}
+
+// Subscribe returns a buffered channel of updates for taskID and an
+// unsubscribe func the caller must invoke when done listening.
+func (p *pubsub) Subscribe(taskID string) (<-chan Update, func()) {
+	ch := make(chan Update, subscriberBuffer)
+
+	p.mu.Lock()
+	if p.subs[taskID] == nil {
+		p.subs[taskID] = make(map[chan Update]struct{})
+	}
+	p.subs[taskID][ch] = struct{}{}
+	p.mu.Unlock()
+
+	unsubscribe := func() {
+		p.mu.Lock()
+		delete(p.subs[taskID], ch)
+		if len(p.subs[taskID]) == 0 {
+			delete(p.subs, taskID)
+		}
+		p.mu.Unlock()
+	}
+	return ch, unsubscribe
+}
+
+// publish fans an update out to every current subscriber of t.ID. A
+// subscriber whose buffer is full is slow; rather than block the
+// publisher (the worker or an HTTP handler goroutine), we drop the
+// update for that subscriber — it will see the next one, and can always
+// fall back to GET /api/v1/tasks/:id for the authoritative state.
+func (p *pubsub) publish(t *Task) {
+	p.mu.Lock()
+	p.seq++
+	u := Update{Seq: p.seq, ID: t.ID, Status: t.Status, Progress: t.Progress, Stage: t.Status}
+	subs := p.subs[t.ID]
+	chans := make([]chan Update, 0, len(subs))
+	for ch := range subs {
+		chans = append(chans, ch)
+	}
+	p.mu.Unlock()
+
+	for _, ch := range chans {
+		select {
+		case ch <- u:
+		default:
+		}
+	}
+}
@@ -1,59 +1,222 @@
 package store
 
 import (
+	"context"
 	"encoding/json"
 	"os"
 	"sync"
+	"time"
 )
 
 // Task represents a processing job state.
 type Task struct {
 	ID            string `json:"id"`
+	OwnerID       string `json:"owner_id"`
 	Status        string `json:"status"`
 	Progress      int    `json:"progress"`
 	SourcePath    string `json:"source_path"`
 	AnnotatedPath string `json:"annotated_path"`
 	ReportPath    string `json:"report_path"`
+	ArchivePath   string `json:"archive_path,omitempty"`
+	// HandoffToken is the short-lived JWT UploadHandler issued proving a
+	// worker is authorized to touch SourcePath; it's never serialized to
+	// API clients.
+	HandoffToken string `json:"-"`
+	// SHA256 is SourcePath's content hash, used by FindCompletedByHash to
+	// dedupe a second upload of identical bytes onto an already-finished
+	// task once the first has dropped out of the queue's in-flight
+	// idempotency index.
+	SHA256    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	// Version increments on every write and backs optimistic-concurrency
+	// transitions in Backend.CompareAndSwap.
+	Version uint64 `json:"version"`
 }
 
-// Store keeps tasks in memory with simple locking.
+// Store is the façade the rest of the gateway talks to; it delegates
+// persistence to a pluggable Backend so callers don't need to know
+// whether tasks live in memory or in a durable embedded/remote store.
 type Store struct {
-	mu    sync.RWMutex
-	tasks map[string]*Task
+	backend Backend
+	pubsub  *pubsub
+
+	cancelMu sync.Mutex
+	cancels  map[string]context.CancelFunc
 }
 
-// NewStore constructs an empty task store.
+// NewStore constructs a Store backed by an in-memory map. This preserves
+// the original zero-config behavior for local runs and tests.
 func NewStore() *Store {
-	return &Store{tasks: make(map[string]*Task)}
+	return NewStoreWithBackend(NewMemoryBackend())
+}
+
+// NewStoreWithBackend constructs a Store over an arbitrary Backend, e.g.
+// NewBoltBackend for single-node durability.
+func NewStoreWithBackend(b Backend) *Store {
+	return &Store{backend: b, pubsub: newPubsub(), cancels: make(map[string]context.CancelFunc)}
 }
 
-// AddTask inserts a task.
-func (s *Store) AddTask(t *Task) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.tasks[t.ID] = t
+// AddTask inserts a task, stamping CreatedAt/UpdatedAt if unset.
+func (s *Store) AddTask(t *Task) error {
+	now := time.Now()
+	if t.CreatedAt.IsZero() {
+		t.CreatedAt = now
+	}
+	t.UpdatedAt = now
+	if err := s.backend.AddTask(t); err != nil {
+		return err
+	}
+	s.pubsub.publish(t)
+	return nil
 }
 
 // GetTask returns a task by ID.
 func (s *Store) GetTask(id string) (*Task, bool) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	t, ok := s.tasks[id]
+	t, ok, err := s.backend.GetTask(id)
+	if err != nil {
+		return nil, false
+	}
 	return t, ok
 }
 
-// UpdateTask applies a mutation if the task exists.
+// UpdateTask applies a mutation if the task exists, bumping UpdatedAt and
+// Version as part of the same backend write, then publishes the new
+// state to any subscribers of Subscribe(id).
 func (s *Store) UpdateTask(id string, fn func(*Task)) bool {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	t, ok := s.tasks[id]
+	var updated *Task
+	ok, err := s.backend.UpdateTask(id, func(t *Task) {
+		fn(t)
+		t.UpdatedAt = time.Now()
+		updated = t
+	})
+	if !ok || err != nil {
+		return false
+	}
+	s.pubsub.publish(updated)
+	return true
+}
+
+// GetTaskForOwner returns a task only if it exists and belongs to
+// ownerID, so a caller who guesses another tenant's task ID sees the
+// same "not found" response as a nonexistent one.
+func (s *Store) GetTaskForOwner(id, ownerID string) (*Task, bool) {
+	t, ok := s.GetTask(id)
+	if !ok || t.OwnerID != ownerID {
+		return nil, false
+	}
+	return t, true
+}
+
+// UpdateTaskForOwner applies fn only if the task exists and belongs to
+// ownerID.
+func (s *Store) UpdateTaskForOwner(id, ownerID string, fn func(*Task)) bool {
+	if _, ok := s.GetTaskForOwner(id, ownerID); !ok {
+		return false
+	}
+	return s.UpdateTask(id, fn)
+}
+
+// RegisterCancel associates cancel with taskID so a later CancelTask
+// call interrupts whichever worker registered it. The returned release
+// func must be called (typically via defer) once that worker stops
+// listening for cancellation, so CancelTask doesn't hold a stale entry.
+func (s *Store) RegisterCancel(taskID string, cancel context.CancelFunc) (release func()) {
+	s.cancelMu.Lock()
+	s.cancels[taskID] = cancel
+	s.cancelMu.Unlock()
+	return func() {
+		s.cancelMu.Lock()
+		delete(s.cancels, taskID)
+		s.cancelMu.Unlock()
+	}
+}
+
+// CancelTask cancels the context of whichever worker is currently
+// processing taskID. It returns false if no worker is registered for
+// that ID, e.g. because it hasn't been dequeued yet or already finished.
+func (s *Store) CancelTask(taskID string) bool {
+	s.cancelMu.Lock()
+	cancel, ok := s.cancels[taskID]
+	s.cancelMu.Unlock()
 	if !ok {
 		return false
 	}
-	fn(t)
+	cancel()
 	return true
 }
 
+// CancelAll cancels every currently-registered in-flight task. It backs
+// the hard stop a shutdown sequence falls back to once a graceful drain
+// deadline elapses.
+func (s *Store) CancelAll() {
+	s.cancelMu.Lock()
+	cancels := make([]context.CancelFunc, 0, len(s.cancels))
+	for _, cancel := range s.cancels {
+		cancels = append(cancels, cancel)
+	}
+	s.cancelMu.Unlock()
+	for _, cancel := range cancels {
+		cancel()
+	}
+}
+
+// Subscribe returns a channel of live Update events for taskID and an
+// unsubscribe func that callers must invoke (typically via defer) once
+// they stop reading, e.g. when an SSE or WebSocket client disconnects.
+func (s *Store) Subscribe(taskID string) (<-chan Update, func()) {
+	return s.pubsub.Subscribe(taskID)
+}
+
+// List returns every task known to the backend.
+func (s *Store) List() ([]*Task, error) {
+	return s.backend.List()
+}
+
+// Delete removes a task.
+func (s *Store) Delete(id string) error {
+	return s.backend.Delete(id)
+}
+
+// FindCompletedByHash returns a Completed task owned by ownerID whose
+// content hash is sha, if any. It's the dedup path a resumable upload's
+// finalize step falls back to once the original upload has finished and
+// aged out of the queue's idempotency index, so re-finalizing identical
+// bytes still returns the original task_id instead of re-auditing it.
+func (s *Store) FindCompletedByHash(ownerID, sha string) (*Task, bool) {
+	all, err := s.backend.List()
+	if err != nil {
+		return nil, false
+	}
+	for _, t := range all {
+		if t.OwnerID == ownerID && t.SHA256 == sha && t.Status == "Completed" {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// Recoverable returns tasks left in Parsing, Auditing or Cancelling
+// (Queued tasks are not included), used by the
+// worker on startup to resume or fail out anything interrupted by a
+// restart.
+func (s *Store) Recoverable() ([]*Task, error) {
+	all, err := s.backend.List()
+	if err != nil {
+		return nil, err
+	}
+	var out []*Task
+	for _, t := range all {
+		switch t.Status {
+		case "Parsing", "Auditing", "Cancelling":
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
 // WriteReport writes a JSON report to disk.
 func WriteReport(path string, data any) error {
 	f, err := os.Create(path)
@@ -1,6 +1,9 @@
 package store
 
-import "testing"
+import (
+	"path/filepath"
+	"testing"
+)
 
 func TestStoreAddGetUpdate(t *testing.T) {
 	s := NewStore()
@@ -25,3 +28,65 @@ func TestStoreAddGetUpdate(t *testing.T) {
 		t.Fatalf("task not updated, got: %+v", got)
 	}
 }
+
+func TestStoreRecoverable(t *testing.T) {
+	s := NewStore()
+	s.AddTask(&Task{ID: "parsing", Status: "Parsing"})
+	s.AddTask(&Task{ID: "auditing", Status: "Auditing"})
+	s.AddTask(&Task{ID: "done", Status: "Completed"})
+
+	stuck, err := s.Recoverable()
+	if err != nil {
+		t.Fatalf("recoverable: %v", err)
+	}
+	if len(stuck) != 2 {
+		t.Fatalf("expected 2 recoverable tasks, got %d", len(stuck))
+	}
+}
+
+func TestGetTaskForOwnerEnforcesOwnership(t *testing.T) {
+	s := NewStore()
+	s.AddTask(&Task{ID: "t1", OwnerID: "tenant-a", Status: "Pending"})
+
+	if _, ok := s.GetTaskForOwner("t1", "tenant-b"); ok {
+		t.Fatalf("expected a different tenant to be denied")
+	}
+	if _, ok := s.GetTaskForOwner("t1", "tenant-a"); !ok {
+		t.Fatalf("expected the owning tenant to succeed")
+	}
+	if ok := s.UpdateTaskForOwner("t1", "tenant-b", func(t *Task) { t.Status = "Hijacked" }); ok {
+		t.Fatalf("expected update from a different tenant to be denied")
+	}
+}
+
+func TestBoltBackendPersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	b, err := NewBoltBackend(path)
+	if err != nil {
+		t.Fatalf("open bolt backend: %v", err)
+	}
+	s := NewStoreWithBackend(b)
+	s.AddTask(&Task{ID: "t1", Status: "Pending"})
+	s.UpdateTask("t1", func(t *Task) { t.Status = "Auditing"; t.Progress = 40 })
+	if err := b.Close(); err != nil {
+		t.Fatalf("close bolt backend: %v", err)
+	}
+
+	reopened, err := NewBoltBackend(path)
+	if err != nil {
+		t.Fatalf("reopen bolt backend: %v", err)
+	}
+	defer reopened.Close()
+
+	got, ok, err := reopened.GetTask("t1")
+	if err != nil || !ok {
+		t.Fatalf("expected task to survive reopen, ok=%v err=%v", ok, err)
+	}
+	if got.Status != "Auditing" || got.Progress != 40 {
+		t.Fatalf("unexpected task after reopen: %+v", got)
+	}
+	if got.Version != 2 {
+		t.Fatalf("expected version 2 after one update, got %d", got.Version)
+	}
+}
@@ -0,0 +1,48 @@
+// Package telemetry centralizes structured logging, Prometheus metrics,
+// and OpenTelemetry tracing so the gateway and its workers can correlate
+// a failing parse call with the HTTP request that triggered it.
+package telemetry
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+type ctxKey int
+
+const taskIDKey ctxKey = iota
+
+// base is the process-wide logger; L(ctx) derives from it per call.
+var base = NewLogger()
+
+// NewLogger builds the process-wide zerolog.Logger, reading its level
+// from GATEWAY_LOG_LEVEL (default "info"). Output is JSON so it can be
+// shipped to a log aggregator without a parsing step.
+func NewLogger() zerolog.Logger {
+	level, err := zerolog.ParseLevel(strings.ToLower(os.Getenv("GATEWAY_LOG_LEVEL")))
+	if err != nil {
+		level = zerolog.InfoLevel
+	}
+	return zerolog.New(os.Stdout).Level(level).With().Timestamp().Logger()
+}
+
+// WithTaskID attaches a task_id to ctx so every log line written via
+// L(ctx) carries it, letting an operator grep one task's lifecycle
+// across the HTTP handler and worker stages.
+func WithTaskID(ctx context.Context, taskID string) context.Context {
+	return context.WithValue(ctx, taskIDKey, taskID)
+}
+
+// L returns the package logger enriched with the task_id from ctx, if
+// any was attached via WithTaskID.
+func L(ctx context.Context) *zerolog.Logger {
+	logger := base
+	if id, ok := ctx.Value(taskIDKey).(string); ok && id != "" {
+		l := logger.With().Str("task_id", id).Logger()
+		return &l
+	}
+	return &logger
+}
@@ -0,0 +1,37 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLIncludesTaskIDFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	orig := base
+	base = zerolog.New(&buf)
+	defer func() { base = orig }()
+
+	ctx := WithTaskID(context.Background(), "t-123")
+	L(ctx).Info().Msg("hello")
+
+	if !strings.Contains(buf.String(), `"task_id":"t-123"`) {
+		t.Fatalf("expected task_id in log output, got: %s", buf.String())
+	}
+}
+
+func TestLWithoutTaskID(t *testing.T) {
+	var buf bytes.Buffer
+	orig := base
+	base = zerolog.New(&buf)
+	defer func() { base = orig }()
+
+	L(context.Background()).Info().Msg("hello")
+
+	if strings.Contains(buf.String(), "task_id") {
+		t.Fatalf("expected no task_id in log output, got: %s", buf.String())
+	}
+}
@@ -0,0 +1,42 @@
+package telemetry
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics exposed on GET /metrics for the gateway and worker to share.
+var (
+	QueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_queue_depth",
+		Help: "Number of tasks waiting to be picked up by a worker.",
+	})
+
+	InFlightTasks = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gateway_in_flight_tasks",
+		Help: "Number of tasks currently being processed by workers.",
+	})
+
+	StageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gateway_stage_duration_seconds",
+		Help:    "Duration of each worker stage.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"stage"})
+
+	GRPCCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gateway_grpc_call_duration_seconds",
+		Help:    "Duration of outbound gRPC calls to the parser/engine/inference services.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	GRPCCallErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gateway_grpc_call_errors_total",
+		Help: "Outbound gRPC call failures by service, method, and status code.",
+	}, []string{"service", "method", "code"})
+
+	UploadFileSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "gateway_upload_file_size_bytes",
+		Help:    "Size of uploaded files.",
+		Buckets: prometheus.ExponentialBuckets(1024, 4, 10), // 1KiB .. ~256MiB
+	})
+)
@@ -0,0 +1,29 @@
+package telemetry
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestLogger is an Echo middleware that logs one structured line per
+// request (method, path, status, latency), replacing echo's default
+// plain-text logger so request logs match the JSON worker/task logs.
+func RequestLogger() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			base.Info().
+				Str("method", c.Request().Method).
+				Str("path", c.Path()).
+				Int("status", c.Response().Status).
+				Dur("latency", time.Since(start)).
+				Err(err).
+				Msg("request")
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/redspiderAI/ai-auditor-core/services/gateway-go"
+
+// InitTracer wires a global TracerProvider that exports spans to
+// OTEL_EXPORTER_OTLP_ENDPOINT (if set) via OTLP/gRPC. When the env var
+// is unset it still installs a provider (so Tracer() always works) but
+// with no exporter configured, matching the "tracing is opt-in locally,
+// on by default once OTEL_EXPORTER_OTLP_ENDPOINT is set" pattern.
+// The returned shutdown func must be called on process exit to flush
+// pending spans.
+func InitTracer(ctx context.Context) (func(context.Context) error, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName("ai-auditor-gateway"),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
+		exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the gateway's named tracer for starting spans around
+// worker stages and outbound gRPC calls.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
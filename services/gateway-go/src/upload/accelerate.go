@@ -0,0 +1,262 @@
+// Package upload implements GitLab-Workhorse-style upload acceleration:
+// a middleware that pre-authorizes with a Rails-style backend, then
+// streams the multipart file part straight into the gateway's
+// storage.Backend while hashing it on the fly, so the handler never has
+// to buffer a large DOCX/PDF in the gateway's own heap and never has to
+// care whether this particular upload arrived accelerated or not —
+// either way it gets back the same kind of backend-managed locator.
+package upload
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+)
+
+// ErrInjectedClientParam is returned when a client supplies a form field
+// that collides with one of the finalize fields Accelerate injects
+// (e.g. "file.path"), which would otherwise let a client spoof an
+// accelerated upload's resolved location or hashes.
+var ErrInjectedClientParam = errors.New("upload: client supplied a reserved field name")
+
+// finalizeSuffixes are the field-name suffixes GitLabFinalizeFields
+// injects in place of the raw file field.
+var finalizeSuffixes = []string{".path", ".name", ".size", ".sha256", ".md5"}
+
+// Config points Accelerate at the pre-authorization backend and the
+// storage.Backend it streams accelerated uploads into. Backend should
+// be the same instance UploadHandler/StatusHandler use, so an
+// accelerated upload's SourcePath round-trips through Open/Stat/Delete
+// exactly like a non-accelerated one.
+type Config struct {
+	PreAuthURL string
+	Backend    storage.Backend
+	Timeout    time.Duration
+	Client     *http.Client
+}
+
+// FileHandler describes where an accelerated upload ended up and the
+// hashes computed while it streamed through, mirroring what GitLab
+// Workhorse attaches to the request it forwards to Rails.
+type FileHandler struct {
+	Name       string
+	SourcePath string // exactly what Backend.Put returned; see storage.Backend
+	Size       int64
+	SHA256     string
+	MD5        string
+}
+
+// GitLabFinalizeFields returns the form fields upload acceleration
+// injects in place of the raw file, keyed by prefix (the original field
+// name, e.g. "file"). A handler reads these instead of calling
+// c.FormFile when the upload arrived pre-accelerated.
+func (fh *FileHandler) GitLabFinalizeFields(prefix string) map[string]string {
+	return map[string]string{
+		prefix + ".path":   fh.SourcePath,
+		prefix + ".name":   fh.Name,
+		prefix + ".size":   fmt.Sprintf("%d", fh.Size),
+		prefix + ".sha256": fh.SHA256,
+		prefix + ".md5":    fh.MD5,
+	}
+}
+
+const contextKeyPrefix = "accelerated:"
+
+// FromContext returns the FileHandler Accelerate attached for field
+// (e.g. "file"), if the request was accelerated.
+func FromContext(c echo.Context, field string) (*FileHandler, bool) {
+	fh, ok := c.Get(contextKeyPrefix + field).(*FileHandler)
+	return fh, ok
+}
+
+// Accelerate pre-authorizes each incoming multipart upload against
+// cfg's backend, streams field's file part straight into cfg.Backend
+// (computing sha256/md5/size as bytes pass through), and replaces that
+// field with the finalize fields before calling next. Requests with no
+// PreAuthURL configured, or whose body isn't multipart/form-data, pass
+// through unchanged.
+func Accelerate(cfg Config, field string) echo.MiddlewareFunc {
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if cfg.PreAuthURL == "" {
+				return next(c)
+			}
+			req := c.Request()
+			mediaType, _, err := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+			if err != nil || mediaType != "multipart/form-data" {
+				return next(c)
+			}
+
+			if err := preAuthorize(req.Context(), client, cfg); err != nil {
+				return c.JSON(http.StatusBadGateway, map[string]string{"error": "pre-authorization failed: " + err.Error()})
+			}
+
+			fh, rewritten, err := streamAndRewrite(req, field, cfg.Backend)
+			if errors.Is(err, ErrInjectedClientParam) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			if err != nil {
+				return c.JSON(http.StatusBadGateway, map[string]string{"error": "upload offload failed: " + err.Error()})
+			}
+
+			c.Set(contextKeyPrefix+field, fh)
+			req.Body = rewritten.body
+			req.Header.Set(echo.HeaderContentType, rewritten.contentType)
+			req.ContentLength = rewritten.contentLength
+			return next(c)
+		}
+	}
+}
+
+// preAuthorize asks the backend whether this upload is allowed to
+// proceed at all (quota, entitlement, etc.); where the bytes actually
+// land is cfg.Backend's concern, not the pre-authorization response's.
+func preAuthorize(ctx context.Context, client *http.Client, cfg Config) error {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.PreAuthURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("pre-authorization backend returned %s", resp.Status)
+	}
+	return nil
+}
+
+// rewrittenBody is the multipart form Accelerate hands to next, with
+// field's raw file part replaced by its GitLabFinalizeFields.
+type rewrittenBody struct {
+	body          io.ReadCloser
+	contentType   string
+	contentLength int64
+}
+
+// streamAndRewrite walks req's multipart parts, streaming the one named
+// field straight into backend while copying every other part unchanged
+// into the rewritten form.
+func streamAndRewrite(req *http.Request, field string, backend storage.Backend) (*FileHandler, *rewrittenBody, error) {
+	mr, err := req.MultipartReader()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	var fh *FileHandler
+
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		name := part.FormName()
+		if name == field && part.FileName() != "" {
+			fh, err = streamPart(req.Context(), part, backend)
+			part.Close()
+			if err != nil {
+				return nil, nil, err
+			}
+			for k, v := range fh.GitLabFinalizeFields(field) {
+				if err := mw.WriteField(k, v); err != nil {
+					return nil, nil, err
+				}
+			}
+			continue
+		}
+
+		if hasReservedSuffix(name, field) {
+			part.Close()
+			return nil, nil, ErrInjectedClientParam
+		}
+
+		w, err := mw.CreatePart(part.Header)
+		if err != nil {
+			part.Close()
+			return nil, nil, err
+		}
+		if _, err := io.Copy(w, part); err != nil {
+			part.Close()
+			return nil, nil, err
+		}
+		part.Close()
+	}
+
+	if fh == nil {
+		return nil, nil, fmt.Errorf("upload: no file part named %q", field)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return fh, &rewrittenBody{
+		body:          io.NopCloser(&buf),
+		contentType:   mw.FormDataContentType(),
+		contentLength: int64(buf.Len()),
+	}, nil
+}
+
+// streamPart copies part into backend under a fresh key, hashing as it
+// goes, so the resulting FileHandler.SourcePath is exactly what
+// backend.Put returned — the same kind of locator UploadHandler's
+// non-accelerated fallback path would have produced for the same bytes.
+func streamPart(ctx context.Context, part *multipart.Part, backend storage.Backend) (*FileHandler, error) {
+	shaHash := sha256.New()
+	md5Hash := md5.New()
+	tee := io.TeeReader(part, io.MultiWriter(shaHash, md5Hash))
+
+	key := uuid.New().String() + ".docx"
+	url, n, err := backend.Put(ctx, key, tee)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileHandler{
+		Name:       part.FileName(),
+		SourcePath: url,
+		Size:       n,
+		SHA256:     hex.EncodeToString(shaHash.Sum(nil)),
+		MD5:        hex.EncodeToString(md5Hash.Sum(nil)),
+	}, nil
+}
+
+func hasReservedSuffix(name, field string) bool {
+	for _, suf := range finalizeSuffixes {
+		if name == field+suf {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package upload
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
+)
+
+func newMultipartUpload(t *testing.T, fields map[string]string, fileField, fileName string, fileBody []byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		if err := mw.WriteField(k, v); err != nil {
+			t.Fatalf("write field %s: %v", k, err)
+		}
+	}
+	if fileField != "" {
+		fw, err := mw.CreateFormFile(fileField, fileName)
+		if err != nil {
+			t.Fatalf("create form file: %v", err)
+		}
+		if _, err := fw.Write(fileBody); err != nil {
+			t.Fatalf("write file body: %v", err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatalf("close writer: %v", err)
+	}
+	return &buf, mw.FormDataContentType()
+}
+
+func TestAccelerateStreamsIntoBackendAndRewritesForm(t *testing.T) {
+	backend, err := storage.NewLocalFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local fs: %v", err)
+	}
+
+	preAuth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer preAuth.Close()
+
+	body, contentType := newMultipartUpload(t, map[string]string{"other": "value"}, "file", "doc.docx", []byte("hello world"))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/upload", body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var sawFields map[string]string
+	next := func(c echo.Context) error {
+		if err := c.Request().ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("parse rewritten form: %v", err)
+		}
+		sawFields = map[string]string{}
+		for k, v := range c.Request().MultipartForm.Value {
+			sawFields[k] = v[0]
+		}
+		return nil
+	}
+
+	mw := Accelerate(Config{PreAuthURL: preAuth.URL, Backend: backend}, "file")
+	if err := mw(next)(c); err != nil {
+		t.Fatalf("middleware error: %v", err)
+	}
+
+	fh, ok := FromContext(c, "file")
+	if !ok {
+		t.Fatalf("expected FileHandler attached to context")
+	}
+	if fh.SourcePath == "" {
+		t.Fatalf("expected SourcePath to be set")
+	}
+
+	rc, err := backend.Open(req.Context(), fh.SourcePath)
+	if err != nil {
+		t.Fatalf("open streamed object via backend: %v", err)
+	}
+	defer rc.Close()
+	data := make([]byte, 11)
+	if _, err := rc.Read(data); err != nil {
+		t.Fatalf("read streamed object: %v", err)
+	}
+	if string(data) != "hello world" {
+		t.Fatalf("streamed object content mismatch: %q", data)
+	}
+
+	if sawFields["other"] != "value" {
+		t.Fatalf("expected untouched field to survive rewrite, got %+v", sawFields)
+	}
+	if sawFields["file.path"] != fh.SourcePath {
+		t.Fatalf("expected file.path finalize field, got %+v", sawFields)
+	}
+	if sawFields["file.sha256"] == "" {
+		t.Fatalf("expected file.sha256 finalize field, got %+v", sawFields)
+	}
+}
+
+func TestAccelerateRejectsInjectedFinalizeField(t *testing.T) {
+	backend, err := storage.NewLocalFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("new local fs: %v", err)
+	}
+
+	preAuth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer preAuth.Close()
+
+	body, contentType := newMultipartUpload(t, map[string]string{"file.path": "/etc/passwd"}, "file", "doc.docx", []byte("hi"))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/upload", body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mw := Accelerate(Config{PreAuthURL: preAuth.URL, Backend: backend}, "file")
+	if err := mw(func(c echo.Context) error { return nil })(c); err != nil {
+		t.Fatalf("middleware error: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
+func TestAccelerateNoopWithoutPreAuthURL(t *testing.T) {
+	body, contentType := newMultipartUpload(t, nil, "file", "doc.docx", []byte("hi"))
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/upload", body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	called := false
+	mw := Accelerate(Config{}, "file")
+	if err := mw(func(c echo.Context) error { called = true; return nil })(c); err != nil {
+		t.Fatalf("middleware error: %v", err)
+	}
+	if !called {
+		t.Fatalf("expected next to be called when PreAuthURL is unset")
+	}
+	if _, ok := FromContext(c, "file"); ok {
+		t.Fatalf("expected no FileHandler attached when middleware is a no-op")
+	}
+}
@@ -6,126 +6,274 @@ package worker
 import (
 	"context"
 	"io"
-	"log"
 	"os"
+	"path/filepath"
 	"time"
 
-	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
 
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/artifacts"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/queue"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/report"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/rpcclient"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
 	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/telemetry"
 
-	// NOTE: adjust to your generated Go proto package path.
+	// NOTE: adjust to your generated Go proto package path. InjectAnnotations
+	// is a new RPC this change adds to that proto; it isn't vendored into
+	// this tree, so the generated stub is assumed rather than checked in.
 	auditorpb "github.com/redspiderAI/ai-auditor-core/shared/protos/go/academic/auditor"
 )
 
+// pool holds the long-lived parser/engine/inference connections for the
+// lifetime of the process, replacing the old per-task grpc.DialContext.
+var pool = rpcclient.NewPool()
+
+func upstreamConfig(name, addrEnv, addrDefault string) rpcclient.UpstreamConfig {
+	return rpcclient.UpstreamConfig{
+		Name:           name,
+		Addr:           getenvDefault(addrEnv, addrDefault),
+		CACertPath:     os.Getenv("GRPC_CLIENT_CA_CERT"),
+		ClientCertPath: os.Getenv("GRPC_CLIENT_CERT"),
+		ClientKeyPath:  os.Getenv("GRPC_CLIENT_KEY"),
+	}
+}
+
 // WorkerGRPC replaces the simulated worker when built with `-tags grpc`.
-func Worker(tasks <-chan string, s *store.Store) {
-	parserAddr := getenvDefault("RUST_PARSER_ADDR", "parser-rs:52051")
-	engineAddr := getenvDefault("JAVA_ENGINE_ADDR", "engine-java:9191")
-	inferenceAddr := getenvDefault("PY_INFERENCE_ADDR", "inference-py:50051")
+// RecoverInterrupted is the caller's responsibility and must run once,
+// before the pool starts. backend is the same Backend
+// UploadHandler/StatusHandler use, so Task.SourcePath is read the same
+// way regardless of STORAGE_DRIVER.
+func Worker(ctx context.Context, q queue.Queue, s *store.Store, backend storage.Backend) {
+	parserCfg := upstreamConfig("parser", "RUST_PARSER_ADDR", "parser-rs:52051")
+	engineCfg := upstreamConfig("engine", "JAVA_ENGINE_ADDR", "engine-java:9191")
+	inferenceCfg := upstreamConfig("inference", "PY_INFERENCE_ADDR", "inference-py:50051")
 
-	for id := range tasks {
-		t, ok := s.GetTask(id)
+	for {
+		item, ok := q.Dequeue(ctx)
 		if !ok {
-			continue
+			return
 		}
-		_ = s.UpdateTask(id, func(t *store.Task) {
-			t.Status = "Parsing"
-			t.Progress = 5
-		})
+		processTask(item.TaskID, q, s, backend, parserCfg, engineCfg, inferenceCfg)
+	}
+}
+
+// processTask owns one task end to end. Its context is deliberately
+// rooted in context.Background(), not Worker's ctx: that ctx is
+// cancelled the instant a shutdown signal arrives (so Worker stops
+// dequeuing new work), but a task already in flight should keep running
+// until it finishes, a caller cancels it via Store.CancelTask
+// (DELETE /api/v1/tasks/:id), or the shutdown sequence's drain deadline
+// calls Store.CancelAll.
+func processTask(id string, q queue.Queue, s *store.Store, backend storage.Backend, parserCfg, engineCfg, inferenceCfg rpcclient.UpstreamConfig) {
+	taskCtx, cancel := context.WithCancel(telemetry.WithTaskID(context.Background(), id))
+	release := s.RegisterCancel(id, cancel)
+	defer release()
+	defer cancel()
+
+	log := telemetry.L(taskCtx)
+	telemetry.InFlightTasks.Inc()
+	defer telemetry.InFlightTasks.Dec()
+
+	// t.HandoffToken is not checked here: see the same comment in
+	// local_worker.go. This worker shares the gateway's Store directly,
+	// so the token's job (proving an out-of-process caller is the
+	// gateway) doesn't apply to in-process dequeue.
+	t, ok := s.GetTask(id)
+	if !ok || t.Status == "Cancelling" {
+		_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Cancelled" })
+		q.Ack(id)
+		return
+	}
+	_ = s.UpdateTask(id, func(t *store.Task) {
+		t.Status = "Parsing"
+		t.Progress = 5
+	})
+
+	dialCtx, dialCancel := context.WithTimeout(taskCtx, 30*time.Second)
+	conn, err := pool.Conn(dialCtx, parserCfg)
+	if err != nil {
+		log.Error().Err(err).Str("addr", parserCfg.Addr).Msg("failed to connect to parser")
+		_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Error: parser connect" })
+		dialCancel()
+		q.Nack(id, err)
+		return
+	}
+	// The parser/engine RPCs take a FilePath on a filesystem they can
+	// open directly, not a storage.Backend locator (a bare local path
+	// for LocalFS, an s3:// or gs:// URL otherwise), so SourcePath is
+	// read through backend and materialized to a local temp file first.
+	localSource, cleanupSource, err := materializeLocal(taskCtx, backend, t.SourcePath)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to read source from backend")
+		_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Error: parse" })
+		dialCancel()
+		q.Nack(id, err)
+		return
+	}
+	defer cleanupSource()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		conn, err := grpc.DialContext(ctx, parserAddr, grpc.WithInsecure(), grpc.WithBlock())
+	client := auditorpb.NewDocumentAuditorClient(conn)
+	parsed, err := timedCall(dialCtx, "parser", "ParseDocument", func(c context.Context) (*auditorpb.ParsedDocument, error) {
+		return client.ParseDocument(c, &auditorpb.ParseRequest{FilePath: localSource})
+	})
+	dialCancel()
+	if err != nil {
+		log.Error().Err(err).Msg("parse error")
+		_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Error: parse" })
+		q.Nack(id, err)
+		return
+	}
+
+	_ = s.UpdateTask(id, func(t *store.Task) {
+		t.Status = "Auditing"
+		t.Progress = 40
+	})
+
+	auditCtx, auditCancel := context.WithTimeout(taskCtx, 45*time.Second)
+	defer auditCancel()
+	connEngine, err := pool.Conn(auditCtx, engineCfg)
+	if err != nil {
+		log.Error().Err(err).Str("addr", engineCfg.Addr).Msg("failed to connect to engine")
+		_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Error: engine connect" })
+		q.Nack(id, err)
+		return
+	}
+	engineClient := auditorpb.NewDocumentAuditorClient(connEngine)
+
+	connInf, err := pool.Conn(auditCtx, inferenceCfg)
+	if err != nil {
+		log.Error().Err(err).Str("addr", inferenceCfg.Addr).Msg("failed to connect to inference")
+		_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Error: inference connect" })
+		q.Nack(id, err)
+		return
+	}
+	infClient := auditorpb.NewDocumentAuditorClient(connInf)
+
+	auditReq := &auditorpb.AuditRequest{Data: parsed}
+	semanticReq := &auditorpb.SemanticRequest{Sections: parsed.Sections}
+
+	chAudit := make(chan *auditorpb.AuditResponse, 1)
+	chSem := make(chan *auditorpb.AuditResponse, 1)
+
+	go func() {
+		resp, err := timedCall(auditCtx, "engine", "AuditRules", func(c context.Context) (*auditorpb.AuditResponse, error) {
+			return engineClient.AuditRules(c, auditReq)
+		})
 		if err != nil {
-			log.Printf("failed to dial parser: %v", err)
-			_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Error: parser connect" })
-			cancel()
-			continue
+			log.Error().Err(err).Msg("AuditRules error")
+			chAudit <- &auditorpb.AuditResponse{}
+			return
 		}
-		client := auditorpb.NewDocumentAuditorClient(conn)
-		parsed, err := client.ParseDocument(ctx, &auditorpb.ParseRequest{FilePath: t.SourcePath})
-		conn.Close()
-		cancel()
+		chAudit <- resp
+	}()
+
+	go func() {
+		resp, err := timedCall(auditCtx, "inference", "AnalyzeSemantics", func(c context.Context) (*auditorpb.AuditResponse, error) {
+			return infClient.AnalyzeSemantics(c, semanticReq)
+		})
 		if err != nil {
-			log.Printf("parse error: %v", err)
-			_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Error: parse" })
-			continue
+			log.Error().Err(err).Msg("AnalyzeSemantics error")
+			chSem <- &auditorpb.AuditResponse{}
+			return
 		}
+		chSem <- resp
+	}()
 
-		_ = s.UpdateTask(id, func(t *store.Task) {
-			t.Status = "Auditing"
-			t.Progress = 40
+	auditResp := <-chAudit
+	semResp := <-chSem
+
+	issues := append(auditResp.Issues, semResp.Issues...)
+	annotated := filepath.Join("..", "temp_docs", id+"-annotated.docx")
+	reportPath := filepath.Join("..", "temp_docs", id+"-report.json")
+
+	annotateResp, err := timedCall(auditCtx, "parser", "InjectAnnotations", func(c context.Context) (*auditorpb.AnnotateResponse, error) {
+		return client.InjectAnnotations(c, &auditorpb.AnnotateRequest{
+			FilePath: localSource,
+			Issues:   issues,
 		})
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("InjectAnnotations error, falling back to an unannotated copy")
+		_ = copyFromSource(taskCtx, backend, t.SourcePath, annotated)
+	} else if err := os.WriteFile(annotated, annotateResp.AnnotatedDocx, 0o644); err != nil {
+		log.Error().Err(err).Msg("failed to write annotated docx")
+		_ = copyFromSource(taskCtx, backend, t.SourcePath, annotated)
+	}
 
-		ctx, cancel = context.WithTimeout(context.Background(), 45*time.Second)
-		connEngine, err := grpc.DialContext(ctx, engineAddr, grpc.WithInsecure(), grpc.WithBlock())
-		if err != nil {
-			log.Printf("engine dial err: %v", err)
-			_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Error: engine connect" })
-			cancel()
-			continue
-		}
-		engineClient := auditorpb.NewDocumentAuditorClient(connEngine)
+	_ = store.WriteReport(reportPath, report.New(id, toReportIssues(issues)))
 
-		connInf, err := grpc.DialContext(ctx, inferenceAddr, grpc.WithInsecure(), grpc.WithBlock())
-		if err != nil {
-			log.Printf("inference dial err: %v", err)
-			_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Error: inference connect" })
-			connEngine.Close()
-			cancel()
-			continue
-		}
-		infClient := auditorpb.NewDocumentAuditorClient(connInf)
-
-		auditReq := &auditorpb.AuditRequest{Data: parsed}
-		semanticReq := &auditorpb.SemanticRequest{Sections: parsed.Sections}
-
-		chAudit := make(chan *auditorpb.AuditResponse, 1)
-		chSem := make(chan *auditorpb.AuditResponse, 1)
-
-		go func() {
-			ctx2, _ := context.WithTimeout(context.Background(), 25*time.Second)
-			resp, err := engineClient.AuditRules(ctx2, auditReq)
-			if err != nil {
-				log.Printf("AuditRules error: %v", err)
-				chAudit <- &auditorpb.AuditResponse{}
-				return
-			}
-			chAudit <- resp
-		}()
-
-		go func() {
-			ctx2, _ := context.WithTimeout(context.Background(), 25*time.Second)
-			resp, err := infClient.AnalyzeSemantics(ctx2, semanticReq)
-			if err != nil {
-				log.Printf("AnalyzeSemantics error: %v", err)
-				chSem <- &auditorpb.AuditResponse{}
-				return
-			}
-			chSem <- resp
-		}()
-
-		auditResp := <-chAudit
-		semResp := <-chSem
-
-		connEngine.Close()
-		connInf.Close()
-		cancel()
-
-		issues := append(auditResp.Issues, semResp.Issues...)
-		annotated := t.SourcePath + "-annotated.docx"
-		report := t.SourcePath + "-report.json"
-
-		_ = copyFile(t.SourcePath, annotated)
-		_ = store.WriteReport(report, map[string]any{"task_id": id, "issues": issues})
-
-		_ = s.UpdateTask(id, func(t *store.Task) {
-			t.AnnotatedPath = annotated
-			t.ReportPath = report
-			t.Status = "Completed"
-			t.Progress = 100
+	// MetadataHandler/ExtractHandler (src/artifacts/handlers.go) serve
+	// out of ArchivePath; build the bundle now so a task that just
+	// finished is immediately reachable through those endpoints, not
+	// only the raw annotated/report paths below. The key is bare (not
+	// joined with temp_docs) since backend.Put resolves it against its
+	// own root/bucket, same as UploadHandler's source keys.
+	bundleKey := id + "-bundle.zip"
+	archivePath, err := artifacts.BuildBundle(taskCtx, backend, bundleKey, map[string]string{
+		filepath.Base(annotated):  annotated,
+		filepath.Base(reportPath): reportPath,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to build artifact bundle")
+	}
+
+	_ = s.UpdateTask(id, func(t *store.Task) {
+		t.AnnotatedPath = annotated
+		t.ReportPath = reportPath
+		t.ArchivePath = archivePath
+		t.Status = "Completed"
+		t.Progress = 100
+	})
+	q.Ack(id)
+	log.Info().Msg("task completed")
+}
+
+// timedCall wraps a single gRPC call with a trace span and the
+// gateway_grpc_call_duration_seconds/gateway_grpc_call_errors_total
+// metrics, so latency and error codes for the four outbound calls are
+// comparable regardless of which upstream they hit.
+func timedCall[T any](ctx context.Context, service, method string, fn func(context.Context) (T, error)) (T, error) {
+	ctx, span := telemetry.Tracer().Start(ctx, service+"."+method)
+	defer span.End()
+
+	start := time.Now()
+	resp, err := fn(ctx)
+	telemetry.GRPCCallDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		telemetry.GRPCCallErrors.WithLabelValues(service, method, grpcStatusCode(err)).Inc()
+	}
+	return resp, err
+}
+
+func grpcStatusCode(err error) string {
+	if s, ok := status.FromError(err); ok {
+		return s.Code().String()
+	}
+	return "unknown"
+}
+
+// toReportIssues maps the engine/inference wire format onto our
+// renderer-agnostic report.Issue so both AuditRules and AnalyzeSemantics
+// findings flow through the same JSON/SARIF/HTML paths.
+func toReportIssues(issues []*auditorpb.Issue) []report.Issue {
+	out := make([]report.Issue, 0, len(issues))
+	for _, i := range issues {
+		out = append(out, report.Issue{
+			RuleID:   i.RuleId,
+			Severity: i.Severity,
+			Location: report.Location{
+				Section:   int(i.Section),
+				Paragraph: int(i.Paragraph),
+				Run:       int(i.Run),
+			},
+			Message:      i.Message,
+			SuggestedFix: i.SuggestedFix,
+			Evidence:     i.Evidence,
 		})
 	}
+	return out
 }
 
 func getenvDefault(key, def string) string {
@@ -135,8 +283,12 @@ func getenvDefault(key, def string) string {
 	return def
 }
 
-func copyFile(src, dst string) error {
-	in, err := os.Open(src)
+// copyFromSource reads src (the backend-managed locator in
+// Task.SourcePath) via backend and writes it to dst on local disk, used
+// as the unannotated fallback when InjectAnnotations fails or its
+// response can't be written out.
+func copyFromSource(ctx context.Context, backend storage.Backend, src, dst string) error {
+	in, err := backend.Open(ctx, src)
 	if err != nil {
 		return err
 	}
@@ -149,3 +301,31 @@ func copyFile(src, dst string) error {
 	_, err = io.Copy(out, in)
 	return err
 }
+
+// materializeLocal copies src (a storage.Backend locator) to a local
+// temp file, since the parser/engine RPCs' FilePath field expects a
+// path on a filesystem they can open directly rather than a Backend
+// locator. The returned cleanup removes the temp copy once the caller
+// is done with it.
+func materializeLocal(ctx context.Context, backend storage.Backend, src string) (path string, cleanup func(), err error) {
+	rc, err := backend.Open(ctx, src)
+	if err != nil {
+		return "", nil, err
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp("", "worker-source-*")
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := io.Copy(tmp, rc); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", nil, err
+	}
+	return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+}
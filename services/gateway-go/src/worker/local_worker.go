@@ -1,61 +1,158 @@
 package worker
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/artifacts"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/queue"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/report"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/storage"
 	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/telemetry"
 )
 
-// Worker simulates processing (parse -> audit -> report -> annotate).
-func Worker(tasks <-chan string, s *store.Store) {
-	for id := range tasks {
-		if ok := s.UpdateTask(id, func(t *store.Task) {
-			t.Status = "Parsing"
-			t.Progress = 10
-		}); !ok {
-			continue
+// Worker simulates processing (parse -> audit -> report -> annotate),
+// pulling task IDs off q until ctx is cancelled. Call it once per pool
+// slot via workerpool.Run to bound concurrency; RecoverInterrupted is the
+// caller's responsibility and must run once, before the pool starts.
+// backend is the same Backend UploadHandler/StatusHandler use, so
+// Task.SourcePath is read the same way regardless of STORAGE_DRIVER.
+func Worker(ctx context.Context, q queue.Queue, s *store.Store, backend storage.Backend) {
+	for {
+		item, ok := q.Dequeue(ctx)
+		if !ok {
+			return
 		}
+		processTask(item.TaskID, q, s, backend)
+	}
+}
 
-		time.Sleep(1 * time.Second) // simulate parse
+// processTask owns one task end to end, including its own cancellable
+// context so DELETE /api/v1/tasks/:id can interrupt it mid-stage via
+// Store.CancelTask without affecting any other in-flight task. Its
+// context is deliberately NOT derived from Worker's ctx: that ctx is
+// cancelled the instant a shutdown signal arrives (so Worker stops
+// dequeuing new work), but an already-running task should keep going
+// until the shutdown sequence's drain deadline calls Store.CancelAll.
+func processTask(id string, q queue.Queue, s *store.Store, backend storage.Backend) {
+	taskCtx, cancel := context.WithCancel(telemetry.WithTaskID(context.Background(), id))
+	release := s.RegisterCancel(id, cancel)
+	defer release()
+	defer cancel()
 
-		_ = s.UpdateTask(id, func(t *store.Task) {
-			t.Status = "Auditing"
-			t.Progress = 40
-		})
+	telemetry.InFlightTasks.Inc()
+	defer telemetry.InFlightTasks.Dec()
 
-		for p := 50; p <= 90; p += 10 {
-			time.Sleep(800 * time.Millisecond)
-			_ = s.UpdateTask(id, func(t *store.Task) { t.Progress = p })
-		}
+	// t.HandoffToken is not checked here: it proves to an out-of-process
+	// caller of WorkerCallbackHandler that the gateway enqueued this
+	// exact task/file/hash, but this worker shares the same Store and
+	// trust boundary as the gateway that enqueued it, so there's nothing
+	// for a token to prove. Gating in-process pickup on it as well just
+	// dead-letters valid tasks once they sit in the queue past the
+	// token's short TTL.
+	t, ok := s.GetTask(id)
+	if !ok || t.Status == "Cancelling" {
+		finishCancelled(id, q, s)
+		return
+	}
+
+	if ok := s.UpdateTask(id, func(t *store.Task) {
+		t.Status = "Parsing"
+		t.Progress = 10
+	}); !ok {
+		q.Ack(id)
+		return
+	}
+
+	parseStart := time.Now()
+	if !sleepOrCancel(taskCtx, 1*time.Second) {
+		finishCancelled(id, q, s)
+		return
+	}
+	telemetry.StageDuration.WithLabelValues("parse").Observe(time.Since(parseStart).Seconds())
+	telemetry.L(taskCtx).Info().Msg("parse complete")
 
-		annotated := filepath.Join("..", "temp_docs", id+"-annotated.docx")
-		report := filepath.Join("..", "temp_docs", id+"-report.json")
+	_ = s.UpdateTask(id, func(t *store.Task) {
+		t.Status = "Auditing"
+		t.Progress = 40
+	})
 
-		if t, ok := s.GetTask(id); ok {
-			_ = copyFile(t.SourcePath, annotated)
+	auditStart := time.Now()
+	for p := 50; p <= 90; p += 10 {
+		if !sleepOrCancel(taskCtx, 800*time.Millisecond) {
+			finishCancelled(id, q, s)
+			return
 		}
+		_ = s.UpdateTask(id, func(t *store.Task) { t.Progress = p })
+	}
+	telemetry.StageDuration.WithLabelValues("audit").Observe(time.Since(auditStart).Seconds())
+	telemetry.L(taskCtx).Info().Msg("audit complete")
+
+	annotated := filepath.Join("..", "temp_docs", id+"-annotated.docx")
+	reportPath := filepath.Join("..", "temp_docs", id+"-report.json")
+
+	if t, ok := s.GetTask(id); ok {
+		_ = copyFromSource(taskCtx, backend, t.SourcePath, annotated)
+	}
+
+	_ = store.WriteReport(reportPath, report.New(id, nil))
+
+	// MetadataHandler/ExtractHandler (src/artifacts/handlers.go) serve
+	// out of ArchivePath; build the bundle now so a task that just
+	// finished is immediately reachable through those endpoints, not
+	// only the raw annotated/report paths below. The key is bare (not
+	// joined with temp_docs) since backend.Put resolves it against its
+	// own root/bucket, same as UploadHandler's source keys.
+	bundleKey := id + "-bundle.zip"
+	archivePath, err := artifacts.BuildBundle(taskCtx, backend, bundleKey, map[string]string{
+		filepath.Base(annotated):  annotated,
+		filepath.Base(reportPath): reportPath,
+	})
+	if err != nil {
+		telemetry.L(taskCtx).Error().Err(err).Msg("failed to build artifact bundle")
+	}
+
+	_ = s.UpdateTask(id, func(t *store.Task) {
+		t.AnnotatedPath = annotated
+		t.ReportPath = reportPath
+		t.ArchivePath = archivePath
+		t.Status = "Completed"
+		t.Progress = 100
+	})
+	q.Ack(id)
+	telemetry.L(taskCtx).Info().Msg("task completed")
+}
+
+// finishCancelled marks a task cancelled (rather than failed, so
+// dashboards don't count a user-requested stop as an error) and
+// acknowledges it so the queue doesn't redeliver it.
+func finishCancelled(id string, q queue.Queue, s *store.Store) {
+	_ = s.UpdateTask(id, func(t *store.Task) { t.Status = "Cancelled" })
+	q.Ack(id)
+}
 
-		_ = store.WriteReport(report, map[string]any{
-			"task_id":      id,
-			"status":       "completed",
-			"generated_at": time.Now().Format(time.RFC3339),
-			"issues":       []any{},
-		})
-
-		_ = s.UpdateTask(id, func(t *store.Task) {
-			t.AnnotatedPath = annotated
-			t.ReportPath = report
-			t.Status = "Completed"
-			t.Progress = 100
-		})
+// sleepOrCancel waits for d, returning false early if ctx is cancelled
+// first so a task can be interrupted mid-stage instead of only noticing
+// cancellation once the current stage happens to finish.
+func sleepOrCancel(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-time.After(d):
+		return true
+	case <-ctx.Done():
+		return false
 	}
 }
 
-func copyFile(src, dst string) error {
-	in, err := os.Open(src)
+// copyFromSource reads src (the backend-managed locator in
+// Task.SourcePath) via backend and writes it to dst on local disk,
+// standing in for the "annotate" step this simulated worker doesn't
+// actually perform.
+func copyFromSource(ctx context.Context, backend storage.Backend, src, dst string) error {
+	in, err := backend.Open(ctx, src)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/store"
+	"github.com/redspiderAI/ai-auditor-core/services/gateway-go/src/telemetry"
+)
+
+// RecoverInterrupted runs once at worker startup. A task left in
+// "Parsing" or "Auditing" means the previous process died mid-flight;
+// since neither stage is checkpointed yet, the safest move is to fail
+// it out with a reason rather than silently resume from unknown state.
+// A task left in "Cancelling" means a DELETE request raced the process
+// exit and never got acknowledged, so it's resolved as "Cancelled"
+// rather than "Failed". Once per-stage checkpoints land, this is where
+// resume-from-checkpoint will plug in.
+func RecoverInterrupted(s *store.Store) {
+	log := telemetry.L(context.Background())
+
+	stuck, err := s.Recoverable()
+	if err != nil {
+		log.Error().Err(err).Msg("recovery: list failed")
+		return
+	}
+	for _, t := range stuck {
+		stage := t.Status
+		if stage == "Cancelling" {
+			s.UpdateTask(t.ID, func(t *store.Task) { t.Status = "Cancelled" })
+			log.Info().Str("task_id", t.ID).Msg("recovery: resolved cancel requested before restart")
+			continue
+		}
+		s.UpdateTask(t.ID, func(t *store.Task) {
+			t.Status = "Failed"
+			t.LastError = "interrupted during " + stage + " by a gateway restart"
+			t.Attempts++
+		})
+		log.Info().Str("task_id", t.ID).Str("stage", stage).Msg("recovery: marked task failed")
+	}
+}
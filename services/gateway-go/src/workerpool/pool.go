@@ -0,0 +1,27 @@
+// Package workerpool bounds how many tasks worker.Worker processes
+// concurrently and makes that bound configurable, replacing the single
+// unbounded goroutine main.go used to start.
+package workerpool
+
+import (
+	"context"
+	"sync"
+)
+
+// Run launches n goroutines each calling work(ctx), and returns a
+// WaitGroup the caller can Wait() on after cancelling ctx to block
+// until every in-flight task has drained.
+func Run(ctx context.Context, n int, work func(ctx context.Context)) *sync.WaitGroup {
+	if n < 1 {
+		n = 1
+	}
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			work(ctx)
+		}()
+	}
+	return &wg
+}
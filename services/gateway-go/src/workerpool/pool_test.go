@@ -0,0 +1,59 @@
+package workerpool
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunLaunchesNWorkersAndDrainsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var running int32
+	var started int32
+	wg := Run(ctx, 3, func(ctx context.Context) {
+		atomic.AddInt32(&started, 1)
+		atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		<-ctx.Done()
+	})
+
+	deadline := time.After(time.Second)
+	for atomic.LoadInt32(&started) < 3 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected 3 workers to start, got %d", atomic.LoadInt32(&started))
+		default:
+		}
+	}
+
+	cancel()
+	waited := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(waited)
+	}()
+
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("workers did not drain after ctx cancellation")
+	}
+
+	if got := atomic.LoadInt32(&running); got != 0 {
+		t.Fatalf("expected 0 workers still running, got %d", got)
+	}
+}
+
+func TestRunDefaultsBelowOneToOne(t *testing.T) {
+	var started int32
+	wg := Run(context.Background(), 0, func(ctx context.Context) {
+		atomic.AddInt32(&started, 1)
+	})
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&started); got != 1 {
+		t.Fatalf("expected Run(0, ...) to launch 1 worker, got %d", got)
+	}
+}